@@ -1,15 +1,32 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/alwaysgolang/hippo-cli/internal/build"
+	"github.com/alwaysgolang/hippo-cli/internal/doctor"
+	"github.com/alwaysgolang/hippo-cli/internal/generate"
+	"github.com/alwaysgolang/hippo-cli/internal/k8s"
+	"github.com/alwaysgolang/hippo-cli/internal/lint"
+	"github.com/alwaysgolang/hippo-cli/internal/modules"
+	"github.com/alwaysgolang/hippo-cli/internal/wiregen"
 )
 
+const usage = `usage:
+  hippo build [--verbose]
+  hippo generate client --spec <openapi.yaml> --name <name> [--out <dir>]
+  hippo add <module>    modules: auth, docker, k8s
+  hippo k8s render [--values <values.yaml>] [--out <file>|-]
+  hippo lint arch
+  hippo doctor
+  hippo wire [check]`
+
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Println("usage: hippo build [--verbose]")
+		fmt.Println(usage)
 		os.Exit(1)
 	}
 
@@ -26,8 +43,82 @@ func main() {
 			fmt.Println("error:", err)
 			os.Exit(1)
 		}
+	case "generate":
+		if err := runGenerate(os.Args[2:]); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(1)
+		}
+	case "add":
+		if len(os.Args) < 3 {
+			fmt.Println("error: module name is required, available:", strings.Join(modules.Names(), ", "))
+			os.Exit(1)
+		}
+		if err := modules.Add(os.Args[2]); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(1)
+		}
+	case "lint":
+		if len(os.Args) < 3 || os.Args[2] != "arch" {
+			fmt.Println("error: unknown linter, expected: hippo lint arch")
+			os.Exit(1)
+		}
+		if err := lint.RunArch(); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(1)
+		}
+	case "k8s":
+		if err := runK8s(os.Args[2:]); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(1)
+		}
+	case "wire":
+		run := wiregen.RunGenerate
+		if len(os.Args) > 2 && os.Args[2] == "check" {
+			run = wiregen.RunCheck
+		} else if len(os.Args) > 2 {
+			fmt.Println("error: unknown wire command, expected: hippo wire or hippo wire check")
+			os.Exit(1)
+		}
+		if err := run(); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(1)
+		}
+	case "doctor":
+		if err := doctor.Run(); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(1)
+		}
 	default:
 		fmt.Println("unknown command")
+		fmt.Println(usage)
 		os.Exit(1)
 	}
 }
+
+func runGenerate(args []string) error {
+	if len(args) == 0 || args[0] != "client" {
+		return fmt.Errorf("unknown generator, expected: hippo generate client")
+	}
+
+	flags := flag.NewFlagSet("generate client", flag.ExitOnError)
+	opts := generate.ClientOptions{}
+	flags.StringVar(&opts.Spec, "spec", "", "path to the upstream OpenAPI 3 document (yaml or json)")
+	flags.StringVar(&opts.Name, "name", "", "client name, used for the package and CLIENT_<NAME>_BASE_URL")
+	flags.StringVar(&opts.Out, "out", "", "output directory relative to the project root")
+	_ = flags.Parse(args[1:])
+
+	return generate.RunClient(opts)
+}
+
+func runK8s(args []string) error {
+	if len(args) == 0 || args[0] != "render" {
+		return fmt.Errorf("unknown k8s command, expected: hippo k8s render")
+	}
+
+	flags := flag.NewFlagSet("k8s render", flag.ExitOnError)
+	values := flags.String("values", "", "deployment settings, "+k8s.ValuesFile+" by default")
+	out := flags.String("out", "", "output file, "+k8s.ManifestsFile+" by default, - for stdout with secret values")
+	_ = flags.Parse(args[1:])
+
+	return k8s.RunRender(*values, *out)
+}
@@ -3,21 +3,17 @@ package build
 import (
 	"bytes"
 	"fmt"
-	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strings"
 	"time"
 
+	"github.com/alwaysgolang/hippo-cli/internal/project"
 	"github.com/alwaysgolang/hippo-cli/internal/ui"
-	"github.com/alwaysgolang/hippo-cli/templates"
 	"github.com/fatih/color"
 	"github.com/schollz/progressbar/v3"
 )
 
-const templateModuleName = "gotemplate"
-
 type Options struct {
 	Verbose   bool
 	Cinematic bool
@@ -65,7 +61,7 @@ func Run(opts Options) error {
 
 	// 1) copy
 	if err := runStep("Copying template...", func() error {
-		return copyFromEmbed("rest", wd, serviceName)
+		return project.CopyTemplate("rest", wd, serviceName)
 	}, delay); err != nil {
 		return err
 	}
@@ -183,42 +179,6 @@ func runCmd(dir string, verbose bool, name string, args ...string) ([]byte, erro
 	return out, err
 }
 
-func copyFromEmbed(src, dst, moduleName string) error {
-	return fs.WalkDir(templates.FS, src, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		relPath, err := filepath.Rel(src, path)
-		if err != nil {
-			return err
-		}
-
-		if relPath == "." {
-			return nil
-		}
-
-		targetPath := filepath.Join(dst, relPath)
-
-		if d.IsDir() {
-			return os.MkdirAll(targetPath, 0755)
-		}
-
-		// do not overwrite existing files
-		if _, err := os.Stat(targetPath); err == nil {
-			return nil
-		}
-
-		data, err := templates.FS.ReadFile(path)
-		if err != nil {
-			return err
-		}
-
-		content := strings.ReplaceAll(string(data), templateModuleName, moduleName)
-		return os.WriteFile(targetPath, []byte(content), 0644)
-	})
-}
-
 func hasGoMod(path string) bool {
 	_, err := os.Stat(filepath.Join(path, "go.mod"))
 	return err == nil
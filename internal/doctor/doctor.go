@@ -0,0 +1,259 @@
+// Package doctor checks that the machine, and the project in the working directory, have
+// what hippo and the generated code need.
+package doctor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/version"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// MinGoVersion is the oldest toolchain the templates compile with.
+const MinGoVersion = "go1.26"
+
+type Status int
+
+const (
+	OK Status = iota
+	Warn
+	Fail
+)
+
+// Result is the outcome of one check. Fix tells how to resolve anything but OK.
+type Result struct {
+	Name    string
+	Status  Status
+	Message string
+	Fix     string
+}
+
+func ok(name, format string, args ...any) Result {
+	return Result{Name: name, Status: OK, Message: fmt.Sprintf(format, args...)}
+}
+
+func warn(name, message, fix string) Result {
+	return Result{Name: name, Status: Warn, Message: message, Fix: fix}
+}
+
+func fail(name, message, fix string) Result {
+	return Result{Name: name, Status: Fail, Message: message, Fix: fix}
+}
+
+// Run checks the environment and, inside a project, the project itself. It returns an
+// error when any check failed; warnings only get printed.
+func Run() error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	color.Cyan("Environment")
+	results := checkEnvironment(wd)
+	printResults(results)
+
+	if p, found, err := findProject(wd); err != nil {
+		return err
+	} else if found {
+		fmt.Println()
+		color.Cyan("Project %s", p.module)
+		projectResults := checkProject(p)
+		printResults(projectResults)
+		results = append(results, projectResults...)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Status == Fail {
+			failed++
+		}
+	}
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	color.Green("✔ Everything looks good")
+	return nil
+}
+
+func printResults(results []Result) {
+	for _, r := range results {
+		r.Message = strings.ReplaceAll(r.Message, "\n", "\n    ")
+		switch r.Status {
+		case OK:
+			color.Green("✔ %s: %s", r.Name, r.Message)
+		case Warn:
+			color.Yellow("⚠ %s: %s", r.Name, r.Message)
+		case Fail:
+			color.Red("✖ %s: %s", r.Name, r.Message)
+		}
+		if r.Fix != "" {
+			for _, line := range strings.Split(r.Fix, "\n") {
+				fmt.Println("    " + line)
+			}
+		}
+	}
+}
+
+// checkEnvironment checks the tools hippo build and the project makefile run.
+func checkEnvironment(wd string) []Result {
+	results := []Result{checkGo()}
+	if results[0].Status == Fail {
+		return append(results, checkGit()...)
+	}
+
+	env, err := goEnv("GOPATH", "GOBIN", "GOPROXY", "GOFLAGS", "GOMODCACHE")
+	if err != nil {
+		results = append(results, fail("go env", err.Error(), "make sure `go env` works in this directory"))
+	} else {
+		results = append(results, checkGoEnv(env)...)
+	}
+
+	results = append(results, checkGit()...)
+	results = append(results,
+		checkTool("wire", "go install github.com/google/wire/cmd/wire@latest"),
+		checkTool("golangci-lint", "go install github.com/golangci/golangci-lint/v2/cmd/golangci-lint@latest"),
+		checkWritable("working directory", wd),
+	)
+	if env != nil && env["GOMODCACHE"] != "" {
+		if _, err := os.Stat(env["GOMODCACHE"]); err == nil {
+			results = append(results, checkWritable("module cache", env["GOMODCACHE"]))
+		}
+	}
+	return results
+}
+
+func checkGo() Result {
+	if _, err := exec.LookPath("go"); err != nil {
+		return fail("go", "not found in PATH", "install Go "+strings.TrimPrefix(MinGoVersion, "go")+" or newer from https://go.dev/dl")
+	}
+	out, err := exec.Command("go", "env", "GOVERSION").Output()
+	if err != nil {
+		return fail("go", "cannot read the version: "+err.Error(), "run `go env GOVERSION` to see what is wrong")
+	}
+	v := strings.TrimSpace(string(out))
+	if version.Compare(v, MinGoVersion) < 0 {
+		return fail("go", v+" is older than "+MinGoVersion,
+			"install Go "+strings.TrimPrefix(MinGoVersion, "go")+" or newer from https://go.dev/dl, or set GOTOOLCHAIN=auto")
+	}
+	return ok("go", "%s", v)
+}
+
+func goEnv(keys ...string) (map[string]string, error) {
+	out, err := exec.Command("go", append([]string{"env", "-json"}, keys...)...).Output()
+	if err != nil {
+		return nil, err
+	}
+	env := map[string]string{}
+	if err := json.Unmarshal(out, &env); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+func checkGoEnv(env map[string]string) []Result {
+	var results []Result
+
+	bin := env["GOBIN"]
+	if bin == "" && env["GOPATH"] != "" {
+		bin = filepath.Join(filepath.SplitList(env["GOPATH"])[0], "bin")
+	}
+	switch {
+	case env["GOPATH"] == "":
+		results = append(results, warn("GOPATH", "not set", "go env -w GOPATH=$HOME/go"))
+	case !inPath(bin):
+		results = append(results, warn("GOPATH", bin+" is not in PATH, tools from go install will not be found",
+			"add "+bin+" to PATH in your shell profile"))
+	default:
+		results = append(results, ok("GOPATH", "%s", env["GOPATH"]))
+	}
+
+	proxy := env["GOPROXY"]
+	switch {
+	case proxy == "off":
+		results = append(results, fail("GOPROXY", "off, go mod tidy cannot download dependencies",
+			"go env -w GOPROXY=https://proxy.golang.org,direct"))
+	case proxy == "":
+		results = append(results, warn("GOPROXY", "empty, modules are fetched straight from their repositories",
+			"go env -w GOPROXY=https://proxy.golang.org,direct"))
+	default:
+		results = append(results, ok("GOPROXY", "%s", proxy))
+	}
+
+	flags := env["GOFLAGS"]
+	switch {
+	case strings.Contains(flags, "-mod=vendor"):
+		results = append(results, warn("GOFLAGS", flags+", generated projects have no vendor directory",
+			"go env -u GOFLAGS, or run go mod vendor in the project"))
+	case flags == "":
+		results = append(results, ok("GOFLAGS", "not set"))
+	default:
+		results = append(results, ok("GOFLAGS", "%s", flags))
+	}
+	return results
+}
+
+func inPath(dir string) bool {
+	for _, p := range filepath.SplitList(os.Getenv("PATH")) {
+		if filepath.Clean(p) == filepath.Clean(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkGit() []Result {
+	if _, err := exec.LookPath("git"); err != nil {
+		return []Result{fail("git", "not found in PATH", "install git from https://git-scm.com/downloads")}
+	}
+	results := []Result{ok("git", "%s", strings.TrimPrefix(command("git", "--version"), "git version "))}
+
+	name, email := command("git", "config", "user.name"), command("git", "config", "user.email")
+	var fix []string
+	if name == "" {
+		fix = append(fix, `git config --global user.name "Your Name"`)
+	}
+	if email == "" {
+		fix = append(fix, `git config --global user.email "you@example.com"`)
+	}
+	if len(fix) > 0 {
+		results = append(results, fail("git identity", "not configured, hippo build cannot make the initial commit",
+			strings.Join(fix, "\n")))
+	} else {
+		results = append(results, ok("git identity", "%s <%s>", name, email))
+	}
+	return results
+}
+
+func checkTool(name, install string) Result {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return warn(name, "not found in PATH", install)
+	}
+	return ok(name, "%s", path)
+}
+
+func checkWritable(name, dir string) Result {
+	f, err := os.CreateTemp(dir, ".hippo-doctor-*")
+	if err != nil {
+		return fail(name, dir+" is not writable", "fix the permissions of "+dir+" or run hippo from another directory")
+	}
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+	return ok(name, "%s is writable", dir)
+}
+
+// command returns the trimmed output of a command, empty when it fails.
+func command(name string, args ...string) string {
+	out, err := exec.Command(name, args...).Output()
+	if err != nil {
+		return ""
+	}
+	return string(bytes.TrimSpace(out))
+}
@@ -0,0 +1,161 @@
+package doctor
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/joho/godotenv"
+
+	"github.com/alwaysgolang/hippo-cli/internal/project"
+	"github.com/alwaysgolang/hippo-cli/internal/wiregen"
+)
+
+type hippoProject struct {
+	root     string
+	module   string
+	manifest *project.Manifest
+}
+
+// findProject reports whether wd is inside a project generated by hippo.
+func findProject(wd string) (*hippoProject, bool, error) {
+	root, err := project.Root(wd)
+	if errors.Is(err, project.ErrNotProject) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if _, err := os.Stat(filepath.Join(root, project.ProvidersFile)); err != nil {
+		return nil, false, nil
+	}
+	module, err := project.ModulePath(root)
+	if err != nil {
+		return nil, false, err
+	}
+	manifest, err := project.ReadManifest(root)
+	if err != nil {
+		return nil, false, err
+	}
+	return &hippoProject{root: root, module: module, manifest: manifest}, true, nil
+}
+
+// checkProject checks the configuration, the generated wire code and the files copied from
+// the templates.
+func checkProject(p *hippoProject) []Result {
+	results := checkEnvFile(p)
+	results = append(results, checkWireGen(p))
+	return append(results, checkDrift(p)...)
+}
+
+func checkEnvFile(p *hippoProject) []Result {
+	path := filepath.Join(p.root, ".env")
+	values, err := godotenv.Read(path)
+	if errors.Is(err, os.ErrNotExist) {
+		values = map[string]string{}
+	} else if err != nil {
+		return []Result{fail(".env", err.Error(), "fix the syntax of "+path)}
+	}
+
+	vars, err := project.ConfigEnv(p.root)
+	if err != nil {
+		return []Result{fail(".env", "cannot read "+project.ConfigDir+": "+err.Error(), "")}
+	}
+
+	var missing, invalid []string
+	for _, v := range vars {
+		value, set := values[v.Name]
+		if !set {
+			value, set = os.LookupEnv(v.Name)
+		}
+		if !set || value == "" {
+			if v.Required {
+				missing = append(missing, v.Name)
+			}
+			continue
+		}
+		if err := v.Validate(value); err != nil {
+			invalid = append(invalid, err.Error())
+		}
+	}
+
+	var results []Result
+	if len(missing) > 0 {
+		fix := make([]string, len(missing))
+		for i, name := range missing {
+			fix[i] = "add " + name + "=... to .env"
+		}
+		results = append(results, fail(".env", "required variables are not set: "+strings.Join(missing, ", "),
+			strings.Join(fix, "\n")))
+	}
+	if len(invalid) > 0 {
+		results = append(results, fail(".env", "invalid values, the service panics on start", strings.Join(invalid, "\n")))
+	}
+	if len(results) == 0 {
+		results = append(results, ok(".env", "%d variable(s) set, all required ones present", len(values)))
+	}
+	return results
+}
+
+func checkWireGen(p *hippoProject) Result {
+	const name = "wire_gen.go"
+	err := wiregen.Check(p.root)
+	switch {
+	case err == nil:
+		return ok(name, "up to date")
+	case errors.Is(err, wiregen.ErrStale):
+		return fail(name, project.WireGenFile+" is out of date with the providers", "cd "+p.root+" && hippo wire")
+	default:
+		return fail(name, err.Error(), "fix the provider sets in "+project.ProvidersFile+", then run: hippo wire")
+	}
+}
+
+// checkDrift compares the library code under pkg/ with the templates of this hippo
+// version. Everything else is owned by the project and expected to change.
+func checkDrift(p *hippoProject) []Result {
+	sources := []string{"rest"}
+	for _, m := range p.manifest.Modules {
+		sources = append(sources, "modules/"+m)
+	}
+
+	var changed, missing []string
+	for _, src := range sources {
+		files, err := project.TemplateFiles(src, p.module)
+		if err != nil {
+			return []Result{fail("templates", err.Error(), "")}
+		}
+		for rel, want := range files {
+			if !strings.HasPrefix(rel, "pkg/") {
+				continue
+			}
+			got, err := os.ReadFile(filepath.Join(p.root, filepath.FromSlash(rel)))
+			switch {
+			case errors.Is(err, os.ErrNotExist):
+				missing = append(missing, rel)
+			case err != nil:
+				return []Result{fail("templates", err.Error(), "")}
+			case !bytes.Equal(got, want):
+				changed = append(changed, rel)
+			}
+		}
+	}
+	slices.Sort(changed)
+	slices.Sort(missing)
+
+	var results []Result
+	if len(missing) > 0 {
+		results = append(results, warn("templates", "missing: "+strings.Join(missing, ", "),
+			"run hippo build in "+p.root+" to restore them, existing files are kept"))
+	}
+	if len(changed) > 0 {
+		results = append(results, warn("templates", "differ from this hippo version: "+strings.Join(changed, ", "),
+			"keep them if the changes are yours, otherwise delete the files and run hippo build to take the current version"))
+	}
+	if len(results) == 0 {
+		results = append(results, ok("templates", "pkg/ matches this hippo version"))
+	}
+	return results
+}
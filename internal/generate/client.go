@@ -0,0 +1,545 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+
+	"github.com/alwaysgolang/hippo-cli/internal/project"
+	"github.com/alwaysgolang/hippo-cli/internal/wiregen"
+)
+
+const generatedHeader = "// Code generated by hippo generate client. DO NOT EDIT.\n\n"
+
+type ClientOptions struct {
+	Spec string
+	Name string
+	Out  string
+}
+
+func RunClient(opts ClientOptions) error {
+	if opts.Spec == "" || opts.Name == "" {
+		return errors.New("both --spec and --name are required")
+	}
+
+	pkg := packageName(opts.Name)
+	if pkg == "" {
+		return fmt.Errorf("invalid client name %q", opts.Name)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	root, err := project.Root(wd)
+	if err != nil {
+		return err
+	}
+	module, err := project.ModulePath(root)
+	if err != nil {
+		return err
+	}
+
+	spec, err := LoadSpec(opts.Spec)
+	if err != nil {
+		return err
+	}
+
+	out := opts.Out
+	if out == "" {
+		out = filepath.Join("internal", "adapter", "clients", pkg)
+	}
+	outDir := filepath.Join(root, out)
+
+	files, err := newClientGen(spec, opts.Name, module).render()
+	if err != nil {
+		return err
+	}
+	for name, src := range files {
+		if err := project.WriteGoFile(filepath.Join(outDir, name), src); err != nil {
+			return err
+		}
+		color.Green("✔ %s", filepath.Join(out, name))
+	}
+
+	importPath := module + "/" + filepath.ToSlash(out)
+	alias := pkg + "Client"
+	if err := project.AddProvider(root, "ClientSet", alias, importPath, alias+".NewClient"); err != nil {
+		return err
+	}
+	color.Green("✔ %s", project.ProvidersFile)
+
+	baseURL := ""
+	if len(spec.Servers) > 0 {
+		baseURL = spec.Servers[0].URL
+	}
+	envKey := "CLIENT_" + envName(opts.Name) + "_BASE_URL"
+	if err := project.SetEnv(root, envKey, baseURL); err != nil {
+		return err
+	}
+	color.Green("✔ .env: %s", envKey)
+
+	wiregen.Regenerate(root)
+	color.Cyan("👉 Inject *%s.Client where you need it, wire picks it up on the next hippo wire\n", pkg)
+	return nil
+}
+
+type clientGen struct {
+	spec    *Spec
+	pkg     string
+	module  string
+	name    string
+	configs string
+	types   *typeGen
+	imports map[string]bool
+}
+
+func newClientGen(spec *Spec, name, module string) *clientGen {
+	return &clientGen{
+		spec:    spec,
+		pkg:     packageName(name),
+		module:  module,
+		name:    name,
+		configs: strings.ToLower(envName(name)),
+		types:   newTypeGen(spec),
+	}
+}
+
+func (g *clientGen) render() (map[string][]byte, error) {
+	g.types.components()
+	g.imports = map[string]bool{
+		"context":                    true,
+		g.module + "/pkg/httpclient": true,
+	}
+
+	operations, err := g.operations()
+	if err != nil {
+		return nil, err
+	}
+
+	var extra []string
+	if errorsImport := g.module + "/pkg/errors"; g.imports[errorsImport] {
+		delete(g.imports, errorsImport)
+		extra = append(extra, "customErrors "+strconv.Quote(errorsImport))
+	}
+
+	models := generatedHeader + "package " + g.pkg + "\n\n" + importBlock(g.types.imports) + g.types.source()
+	operations = generatedHeader + "package " + g.pkg + "\n\n" + importBlock(g.imports, extra...) + operations
+
+	return map[string][]byte{
+		"client.go":     []byte(g.client()),
+		"models.go":     []byte(models),
+		"operations.go": []byte(operations),
+	}, nil
+}
+
+func (g *clientGen) client() string {
+	imports := map[string]bool{
+		"errors":                      true,
+		"fmt":                         true,
+		"net/http":                    true,
+		"strings":                     true,
+		g.module + "/internal/config": true,
+		g.module + "/pkg/httpclient":  true,
+	}
+	errorsImport := g.module + "/pkg/errors"
+
+	var b strings.Builder
+	b.WriteString(generatedHeader)
+	fmt.Fprintf(&b, "// Package %s is a typed client for %s.\n", g.pkg, specTitle(g.spec, g.name))
+	fmt.Fprintf(&b, "package %s\n\n", g.pkg)
+	b.WriteString(importBlock(imports, "customErrors "+strconv.Quote(errorsImport)))
+	fmt.Fprintf(&b, `const configName = %q
+
+type Client struct {
+	transport *httpclient.Transport
+}
+
+// NewClient builds the client from CLIENT_%s_BASE_URL and CLIENT_%s_TIMEOUT.
+func NewClient(cfg *config.Config) (*Client, func(), error) {
+	clientCfg, ok := cfg.Clients[configName]
+	if !ok || clientCfg.BaseURL == "" {
+		return nil, nil, customErrors.WrapSystemError(errors.New("%s client: CLIENT_%s_BASE_URL is not set"))
+	}
+
+	baseURL := clientCfg.BaseURL
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+
+	transport, cleanup := httpclient.NewTransport(baseURL, nil)
+	if clientCfg.Timeout > 0 {
+		transport.HTTPClient.Timeout = clientCfg.Timeout
+	}
+	return &Client{transport: transport}, cleanup, nil
+}
+
+func wrapStatusError(status int, err error) error {
+	if status == http.StatusNotFound {
+		return customErrors.WrapDataNotFoundError(err)
+	}
+	return err
+}
+
+func joinQuery[T any](values []T) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprint(v)
+	}
+	return strings.Join(parts, ",")
+}
+`, g.configs, envName(g.name), envName(g.name), g.name, envName(g.name))
+	return b.String()
+}
+
+func specTitle(spec *Spec, fallback string) string {
+	if spec.Info.Title == "" {
+		return fallback
+	}
+	if spec.Info.Version == "" {
+		return spec.Info.Title
+	}
+	return spec.Info.Title + " " + spec.Info.Version
+}
+
+func importBlock(imports map[string]bool, extra ...string) string {
+	if len(imports) == 0 && len(extra) == 0 {
+		return ""
+	}
+
+	var std, local []string
+	for path := range imports {
+		if strings.Contains(strings.SplitN(path, "/", 2)[0], ".") || strings.Contains(path, "/pkg/") || strings.Contains(path, "/internal/") {
+			local = append(local, strconv.Quote(path))
+			continue
+		}
+		std = append(std, strconv.Quote(path))
+	}
+	local = append(local, extra...)
+	sort.Strings(std)
+	sort.Strings(local)
+
+	var b strings.Builder
+	b.WriteString("import (\n")
+	for _, path := range std {
+		b.WriteString("\t" + path + "\n")
+	}
+	if len(std) > 0 && len(local) > 0 {
+		b.WriteString("\n")
+	}
+	for _, path := range local {
+		b.WriteString("\t" + path + "\n")
+	}
+	b.WriteString(")\n\n")
+	return b.String()
+}
+
+var methods = []struct {
+	name string
+	get  func(PathItem) *Operation
+}{
+	{http.MethodGet, func(p PathItem) *Operation { return p.Get }},
+	{http.MethodPost, func(p PathItem) *Operation { return p.Post }},
+	{http.MethodPut, func(p PathItem) *Operation { return p.Put }},
+	{http.MethodPatch, func(p PathItem) *Operation { return p.Patch }},
+	{http.MethodDelete, func(p PathItem) *Operation { return p.Delete }},
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+func (g *clientGen) operations() (string, error) {
+	paths := make([]string, 0, len(g.spec.Paths))
+	for path := range g.spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var b strings.Builder
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		item := g.spec.Paths[path]
+		for _, m := range methods {
+			op := m.get(item)
+			if op == nil {
+				continue
+			}
+
+			name := exportedName(op.OperationID)
+			if op.OperationID == "" {
+				name = exportedName(strings.ToLower(m.name) + " " + pathParamPattern.ReplaceAllString(path, "by $1"))
+			}
+			if seen[name] {
+				return "", fmt.Errorf("duplicate operation name %s (%s %s)", name, m.name, path)
+			}
+			seen[name] = true
+
+			if err := g.operation(&b, name, m.name, path, item.Parameters, op); err != nil {
+				return "", fmt.Errorf("%s %s: %w", m.name, path, err)
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+func (g *clientGen) operation(b *strings.Builder, name, method, path string, shared []*Parameter, op *Operation) error {
+	params, err := g.mergeParameters(shared, op.Parameters)
+	if err != nil {
+		return err
+	}
+
+	var pathParams []*Parameter
+	var optionParams []*Parameter
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		param, ok := findParameter(params, match[1], "path")
+		if !ok {
+			param = &Parameter{Name: match[1], In: "path", Required: true, Schema: &Schema{Type: SchemaType{Name: "string"}}}
+		}
+		pathParams = append(pathParams, param)
+	}
+	for _, param := range params {
+		if param.In == "query" || param.In == "header" {
+			optionParams = append(optionParams, param)
+		}
+	}
+
+	args := []string{"ctx context.Context"}
+	uri := strconv.Quote(path)
+	argNames := map[string]bool{}
+	for _, param := range pathParams {
+		arg := argName(param.Name, argNames)
+		args = append(args, arg+" "+g.types.goType(param.Schema, name+exportedName(param.Name)))
+		uri = strings.Replace(uri, "{"+param.Name+"}", `" + url.PathEscape(fmt.Sprint(`+arg+`)) + "`, 1)
+		g.imports["net/url"] = true
+		g.imports["fmt"] = true
+	}
+	uri = strings.TrimSuffix(strings.TrimPrefix(uri, `"" + `), ` + ""`)
+
+	var paramsType string
+	var paramTypes []string
+	if len(optionParams) > 0 {
+		paramsType = g.types.reserve(name + "Params")
+		g.types.decls[paramsType], paramTypes = g.paramsStruct(paramsType, optionParams)
+		args = append(args, "params *"+paramsType)
+	}
+
+	body := "nil"
+	if requestBody, err := g.spec.requestBody(op.RequestBody); err != nil {
+		return err
+	} else if requestBody != nil {
+		if schema := jsonSchema(requestBody.Content); schema != nil {
+			args = append(args, "body "+g.types.goType(schema, name+"Request"))
+			body = "json.RawMessage(payload)"
+			g.imports["encoding/json"] = true
+		}
+	}
+
+	resultType, err := g.resultType(name, op)
+	if err != nil {
+		return err
+	}
+
+	returns := "error"
+	zero := ""
+	result := "&result"
+	if resultType != "" {
+		returns = "(*" + resultType + ", error)"
+		if !pointerable(resultType) {
+			returns = "(" + resultType + ", error)"
+			result = "result"
+		}
+		zero = "nil, "
+	}
+
+	if op.Summary != "" {
+		writeComment(b, name+" "+strings.TrimSuffix(lowerFirst(op.Summary), ".")+".")
+	} else {
+		fmt.Fprintf(b, "// %s calls %s %s.\n", name, method, path)
+	}
+	fmt.Fprintf(b, "func (c *Client) %s(%s) %s {\n", name, strings.Join(args, ", "), returns)
+
+	if body != "nil" {
+		g.imports[g.module+"/pkg/errors"] = true
+		fmt.Fprintf(b, "\tpayload, err := json.Marshal(body)\n\tif err != nil {\n\t\treturn %scustomErrors.WrapSystemError(err)\n\t}\n\n", zero)
+	}
+
+	query := "nil"
+	headers := ""
+	if paramsType != "" {
+		query = "&query"
+		headers = ", headers..."
+		b.WriteString("\tquery := httpclient.JsonMap{}\n\tvar headers []httpclient.Header\n\tif params != nil {\n")
+		for i, param := range optionParams {
+			g.writeParam(b, param, paramTypes[i])
+		}
+		b.WriteString("\t}\n\n")
+	}
+
+	g.imports["net/http"] = true
+	if resultType == "" {
+		fmt.Fprintf(b, "\t_, status, err := c.transport.Do(ctx, http.Method%s, %s, %s, %s%s)\n", methodConst(method), uri, body, query, headers)
+		b.WriteString("\treturn wrapStatusError(status, err)\n}\n\n")
+		return nil
+	}
+	fmt.Fprintf(b, "\tcontent, status, err := c.transport.Do(ctx, http.Method%s, %s, %s, %s%s)\n", methodConst(method), uri, body, query, headers)
+
+	g.imports["encoding/json"] = true
+	g.imports["fmt"] = true
+	g.imports[g.module+"/pkg/errors"] = true
+	fmt.Fprintf(b, `	if err != nil {
+		return nil, wrapStatusError(status, err)
+	}
+
+	var result %s
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, customErrors.WrapExternalServiceError(fmt.Errorf("decode %s response: %%w", err))
+	}
+	return %s, nil
+}
+
+`, resultType, name, result)
+	return nil
+}
+
+func (g *clientGen) resultType(name string, op *Operation) (string, error) {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		response, err := g.spec.response(op.Responses[code])
+		if err != nil {
+			return "", err
+		}
+		if response == nil {
+			continue
+		}
+		if schema := jsonSchema(response.Content); schema != nil {
+			return g.types.goType(schema, name+"Response"), nil
+		}
+	}
+	return "", nil
+}
+
+func (g *clientGen) mergeParameters(shared, own []*Parameter) ([]*Parameter, error) {
+	var result []*Parameter
+	for _, list := range [][]*Parameter{shared, own} {
+		for _, param := range list {
+			resolved, err := g.spec.parameter(param)
+			if err != nil {
+				return nil, err
+			}
+			replaced := false
+			for i, existing := range result {
+				if existing.Name == resolved.Name && existing.In == resolved.In {
+					result[i] = resolved
+					replaced = true
+				}
+			}
+			if !replaced {
+				result = append(result, resolved)
+			}
+		}
+	}
+	return result, nil
+}
+
+func findParameter(params []*Parameter, name, in string) (*Parameter, bool) {
+	for _, param := range params {
+		if param.Name == name && param.In == in {
+			return param, true
+		}
+	}
+	return nil, false
+}
+
+func (g *clientGen) paramsStruct(name string, params []*Parameter) (string, []string) {
+	var b strings.Builder
+	types := make([]string, len(params))
+	fmt.Fprintf(&b, "type %s struct {\n", name)
+	for i, param := range params {
+		types[i] = g.types.goType(param.Schema, name+exportedName(param.Name))
+		fieldType := types[i]
+		if pointerable(fieldType) {
+			fieldType = "*" + fieldType
+		}
+		fmt.Fprintf(&b, "\t%s %s\n", exportedName(param.Name), fieldType)
+	}
+	b.WriteString("}\n")
+	return b.String(), types
+}
+
+func (g *clientGen) writeParam(b *strings.Builder, param *Parameter, fieldType string) {
+	field := "params." + exportedName(param.Name)
+
+	value := "*" + field
+	check := field + " != nil"
+	switch {
+	case fieldType == "any":
+		value = field
+	case fieldType == "json.RawMessage":
+		value = "string(" + field + ")"
+		check = "len(" + field + ") > 0"
+	case !pointerable(fieldType):
+		value = field
+		check = "len(" + field + ") > 0"
+		if strings.HasPrefix(fieldType, "[]") {
+			value = "joinQuery(" + field + ")"
+		}
+	}
+
+	if param.In == "header" {
+		g.imports["fmt"] = true
+		fmt.Fprintf(b, "\t\tif %s {\n\t\t\theaders = append(headers, httpclient.Header{Key: %q, Value: fmt.Sprint(%s)})\n\t\t}\n", check, param.Name, value)
+		return
+	}
+	fmt.Fprintf(b, "\t\tif %s {\n\t\t\tquery[%q] = %s\n\t\t}\n", check, param.Name, value)
+}
+
+// generatedNames are the receiver, arguments, locals and imports of generated methods.
+var generatedNames = map[string]bool{
+	"c": true, "ctx": true, "params": true, "body": true, "payload": true, "err": true, "query": true,
+	"headers": true, "content": true, "status": true, "result": true, "context": true, "fmt": true,
+	"http": true, "json": true, "url": true, "httpclient": true, "customErrors": true,
+}
+
+// argName names the argument of a path parameter so it shadows neither the generated code,
+// predeclared identifiers such as len or nil, nor an earlier argument.
+func argName(param string, taken map[string]bool) string {
+	name := unexportedName(param)
+	if generatedNames[name] || types.Universe.Lookup(name) != nil {
+		name += "Param"
+	}
+	for i := 2; taken[name]; i++ {
+		name = unexportedName(param) + "Param" + strconv.Itoa(i)
+	}
+	taken[name] = true
+	return name
+}
+
+func methodConst(method string) string {
+	return method[:1] + strings.ToLower(method[1:])
+}
+
+func lowerFirst(s string) string {
+	s = strings.TrimSpace(s)
+	first, size := utf8.DecodeRuneInString(s)
+	if _, next := utf8.DecodeRuneInString(s[size:]); s == "" || next > 0 && strings.ToUpper(s[:size+next]) == s[:size+next] {
+		return s
+	}
+	return string(unicode.ToLower(first)) + s[size:]
+}
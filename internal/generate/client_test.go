@@ -0,0 +1,180 @@
+package generate
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alwaysgolang/hippo-cli/internal/project"
+	"github.com/alwaysgolang/hippo-cli/templates"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestClientGolden renders testdata/<case>/spec.yaml, type-checks the package against
+// the rest template (skipped with -short, it is slow) and compares every file with
+// testdata/<case>/<file>.golden. Run go test -update after intended changes.
+func TestClientGolden(t *testing.T) {
+	imp := newTemplateImporter()
+
+	tests := []struct {
+		dir  string
+		name string
+	}{
+		{"basic", "billing-api"},
+		{"collisions", "3ds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			dir := filepath.Join("testdata", tt.dir)
+			spec, err := LoadSpec(filepath.Join(dir, "spec.yaml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			files, err := newClientGen(spec, tt.name, testModule).render()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !testing.Short() {
+				if err := imp.check(tt.name, files); err != nil {
+					t.Error(err)
+				}
+			}
+			for name, src := range files {
+				formatted, err := format.Source(src)
+				if err != nil {
+					t.Fatalf("%s is not valid Go: %v\n%s", name, err, src)
+				}
+
+				golden := filepath.Join(dir, name+".golden")
+				if *update {
+					if err := os.WriteFile(golden, formatted, 0644); err != nil {
+						t.Fatal(err)
+					}
+					continue
+				}
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(formatted, want) {
+					t.Errorf("%s differs from %s:\n%s", name, golden, formatted)
+				}
+			}
+		})
+	}
+}
+
+const testModule = "example.com/app"
+
+// templateImporter resolves imports of the project module and of the template module
+// to the sources of the embedded rest template. Other packages are type-checked from
+// source through the CLI module, which requires the same third-party packages.
+type templateImporter struct {
+	fset     *token.FileSet
+	packages map[string]*types.Package
+	external types.Importer
+}
+
+func newTemplateImporter() *templateImporter {
+	fset := token.NewFileSet()
+	return &templateImporter{
+		fset:     fset,
+		packages: make(map[string]*types.Package),
+		external: importer.ForCompiler(fset, "source", nil),
+	}
+}
+
+// check type-checks the generated files of the client package as hippo generate client
+// writes them.
+func (imp *templateImporter) check(name string, files map[string][]byte) error {
+	_, err := imp.typeCheck(testModule+"/internal/adapter/clients/"+packageName(name), files)
+	return err
+}
+
+func (imp *templateImporter) Import(importPath string) (*types.Package, error) {
+	rel, ok := strings.CutPrefix(importPath, testModule+"/")
+	if !ok {
+		if rel, ok = strings.CutPrefix(importPath, project.TemplateModule+"/"); !ok {
+			return imp.external.Import(importPath)
+		}
+	}
+	if pkg, ok := imp.packages[rel]; ok {
+		return pkg, nil
+	}
+
+	dir := path.Join("rest", rel)
+	entries, err := fs.ReadDir(templates.FS, dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if files[name], err = fs.ReadFile(templates.FS, path.Join(dir, name)); err != nil {
+			return nil, err
+		}
+	}
+	pkg, err := imp.typeCheck(project.TemplateModule+"/"+rel, files)
+	if err != nil {
+		return nil, err
+	}
+	imp.packages[rel] = pkg
+	return pkg, nil
+}
+
+func (imp *templateImporter) typeCheck(importPath string, files map[string][]byte) (*types.Package, error) {
+	parsed := make([]*ast.File, 0, len(files))
+	for name, src := range files {
+		file, err := parser.ParseFile(imp.fset, path.Join(importPath, name), src, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, file)
+	}
+	conf := types.Config{Importer: imp}
+	return conf.Check(importPath, imp.fset, parsed, nil)
+}
+
+func TestNames(t *testing.T) {
+	tests := []struct {
+		fn   func(string) string
+		in   string
+		want string
+	}{
+		{exportedName, "invoice_id", "InvoiceID"},
+		{exportedName, "état", "État"},
+		{exportedName, "名前", "V名前"},
+		{exportedName, "3ds_version", "V3dsVersion"},
+		{unexportedName, "ID", "id"},
+		{unexportedName, "Étape", "étape"},
+		{unexportedName, "type", "typeParam"},
+		{packageName, "billing-api", "billingapi"},
+		{packageName, "3ds", "client3ds"},
+		{packageName, "go", "clientgo"},
+		{packageName, "café", "caf"},
+		{lowerFirst, "Étape of the session.", "étape of the session."},
+		{lowerFirst, "URL of the invoice", "URL of the invoice"},
+	}
+	for _, tt := range tests {
+		if got := tt.fn(tt.in); got != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package generate
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var initialisms = map[string]string{
+	"id":   "ID",
+	"ids":  "IDs",
+	"url":  "URL",
+	"uri":  "URI",
+	"api":  "API",
+	"http": "HTTP",
+	"json": "JSON",
+	"uuid": "UUID",
+	"ip":   "IP",
+	"sql":  "SQL",
+}
+
+func words(s string) []string {
+	var result []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			result = append(result, string(current))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return result
+}
+
+// exportedName turns "invoice_id" or "invoiceId" into "InvoiceID".
+func exportedName(s string) string {
+	var b strings.Builder
+	for _, w := range words(s) {
+		lower := strings.ToLower(w)
+		if initialism, ok := initialisms[lower]; ok {
+			b.WriteString(initialism)
+			continue
+		}
+		b.WriteString(upperFirst(lower))
+	}
+
+	name := b.String()
+	if name == "" {
+		return "Value"
+	}
+	// digits and letters without case, e.g. CJK, cannot start an exported name
+	if first, _ := utf8.DecodeRuneInString(name); !unicode.IsUpper(first) {
+		name = "V" + name
+	}
+	return name
+}
+
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+func unexportedName(s string) string {
+	name := exportedName(s)
+	parts := words(s)
+	if len(parts) > 0 {
+		if initialism, ok := initialisms[strings.ToLower(parts[0])]; ok && strings.HasPrefix(name, initialism) {
+			name = strings.ToLower(initialism) + name[len(initialism):]
+		} else {
+			r, size := utf8.DecodeRuneInString(name)
+			name = string(unicode.ToLower(r)) + name[size:]
+		}
+	}
+	if token.IsKeyword(name) {
+		name += "Param"
+	}
+	return name
+}
+
+// packageName turns "billing-api" into "billingapi". It is the directory of the client
+// as well, so only ASCII letters and digits are kept; names that cannot start a package
+// clause, "3ds" or "go", get a "client" prefix.
+func packageName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		}
+	}
+	name := b.String()
+	if name != "" && (unicode.IsDigit(rune(name[0])) || token.IsKeyword(name)) {
+		name = "client" + name
+	}
+	return name
+}
+
+// envName turns "billing-api" into "BILLING_API".
+func envName(s string) string {
+	return strings.ToUpper(strings.Join(words(s), "_"))
+}
@@ -0,0 +1,186 @@
+package generate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Spec is the subset of OpenAPI 3 the generators understand.
+type Spec struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Components struct {
+	Schemas       map[string]*Schema      `json:"schemas"`
+	Parameters    map[string]*Parameter   `json:"parameters"`
+	RequestBodies map[string]*RequestBody `json:"requestBodies"`
+	Responses     map[string]*Response    `json:"responses"`
+}
+
+type PathItem struct {
+	Parameters []*Parameter `json:"parameters"`
+	Get        *Operation   `json:"get"`
+	Put        *Operation   `json:"put"`
+	Post       *Operation   `json:"post"`
+	Delete     *Operation   `json:"delete"`
+	Patch      *Operation   `json:"patch"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Parameters  []*Parameter         `json:"parameters"`
+	RequestBody *RequestBody         `json:"requestBody"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Ref      string  `json:"$ref"`
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Ref      string               `json:"$ref"`
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref     string               `json:"$ref"`
+	Content map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 SchemaType         `json:"type"`
+	Format               string             `json:"format"`
+	Description          string             `json:"description"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *Schema            `json:"items"`
+	Enum                 []any              `json:"enum"`
+	AllOf                []*Schema          `json:"allOf"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Nullable             bool               `json:"nullable"`
+}
+
+// SchemaType accepts both the 3.0 form ("string") and the 3.1 form (["string", "null"]).
+type SchemaType struct {
+	Name     string
+	Nullable bool
+}
+
+func (t *SchemaType) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		t.Name = single
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("schema type: %w", err)
+	}
+	for _, name := range many {
+		if name == "null" {
+			t.Nullable = true
+			continue
+		}
+		t.Name = name
+	}
+	return nil
+}
+
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		data, err = yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+	}
+
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		return nil, fmt.Errorf("%s: only OpenAPI 3.x is supported, got %q", path, spec.OpenAPI)
+	}
+	return &spec, nil
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func (s *Spec) parameter(p *Parameter) (*Parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	resolved, ok := s.Components.Parameters[refName(p.Ref)]
+	if !ok {
+		return nil, fmt.Errorf("unresolved parameter %s", p.Ref)
+	}
+	return resolved, nil
+}
+
+func (s *Spec) requestBody(b *RequestBody) (*RequestBody, error) {
+	if b == nil || b.Ref == "" {
+		return b, nil
+	}
+	resolved, ok := s.Components.RequestBodies[refName(b.Ref)]
+	if !ok {
+		return nil, fmt.Errorf("unresolved request body %s", b.Ref)
+	}
+	return resolved, nil
+}
+
+func (s *Spec) response(r *Response) (*Response, error) {
+	if r == nil || r.Ref == "" {
+		return r, nil
+	}
+	resolved, ok := s.Components.Responses[refName(r.Ref)]
+	if !ok {
+		return nil, fmt.Errorf("unresolved response %s", r.Ref)
+	}
+	return resolved, nil
+}
+
+func jsonSchema(content map[string]MediaType) *Schema {
+	for contentType, media := range content {
+		if strings.Contains(contentType, "json") {
+			return media.Schema
+		}
+	}
+	return nil
+}
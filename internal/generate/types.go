@@ -0,0 +1,219 @@
+package generate
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// typeGen converts OpenAPI schemas into Go declarations. Inline objects become named
+// structs derived from the place they are declared in.
+type typeGen struct {
+	spec    *Spec
+	decls   map[string]string
+	order   []string
+	imports map[string]bool
+}
+
+func newTypeGen(spec *Spec) *typeGen {
+	return &typeGen{
+		spec:    spec,
+		decls:   make(map[string]string),
+		imports: make(map[string]bool),
+	}
+}
+
+func (g *typeGen) components() {
+	names := make([]string, 0, len(g.spec.Components.Schemas))
+	for name := range g.spec.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// reserve every component name up front so inline types never take them
+	for _, name := range names {
+		g.reserve(exportedName(name))
+	}
+	for _, name := range names {
+		g.named(exportedName(name), g.spec.Components.Schemas[name])
+	}
+}
+
+func (g *typeGen) reserve(name string) string {
+	unique := name
+	for i := 2; ; i++ {
+		if _, taken := g.decls[unique]; !taken {
+			break
+		}
+		unique = name + strconv.Itoa(i)
+	}
+	g.decls[unique] = ""
+	g.order = append(g.order, unique)
+	return unique
+}
+
+func (g *typeGen) named(name string, s *Schema) {
+	var b strings.Builder
+	if s != nil && s.Description != "" {
+		writeComment(&b, name+" "+s.Description)
+	}
+
+	switch {
+	case s != nil && s.Ref == "" && s.Type.Name == "string" && len(s.Enum) > 0:
+		fmt.Fprintf(&b, "type %s string\n\nconst (\n", name)
+		for _, value := range s.Enum {
+			str := fmt.Sprint(value)
+			fmt.Fprintf(&b, "\t%s%s %s = %q\n", name, exportedName(str), name, str)
+		}
+		b.WriteString(")\n")
+	case s != nil && s.Ref == "" && isStruct(s):
+		fmt.Fprintf(&b, "type %s %s\n", name, g.structBody(name, s))
+	default:
+		fmt.Fprintf(&b, "type %s = %s\n", name, g.goType(s, name+"Value"))
+	}
+	g.decls[name] = b.String()
+}
+
+func isStruct(s *Schema) bool {
+	return len(s.Properties) > 0 || len(s.AllOf) > 0
+}
+
+func (g *typeGen) structBody(name string, s *Schema) string {
+	properties, required := g.flatten(s)
+
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	for _, key := range keys {
+		prop := properties[key]
+		fieldName := exportedName(key)
+		fieldType := g.goType(prop, name+fieldName)
+		isRequired := slices.Contains(required, key)
+		if (!isRequired || prop.Nullable || prop.Type.Nullable) && pointerable(fieldType) {
+			fieldType = "*" + fieldType
+		}
+
+		tag := key
+		if !isRequired {
+			tag += ",omitempty"
+		}
+		if prop.Description != "" {
+			writeComment(&b, "\t"+prop.Description)
+		}
+		fmt.Fprintf(&b, "\t%s %s `json:%q`\n", fieldName, fieldType, tag)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// flatten merges allOf members into a single property set.
+func (g *typeGen) flatten(s *Schema) (map[string]*Schema, []string) {
+	properties := make(map[string]*Schema)
+	required := slices.Clone(s.Required)
+	for key, prop := range s.Properties {
+		properties[key] = prop
+	}
+	for _, member := range s.AllOf {
+		if member.Ref != "" {
+			member = g.spec.Components.Schemas[refName(member.Ref)]
+			if member == nil {
+				continue
+			}
+		}
+		memberProps, memberRequired := g.flatten(member)
+		for key, prop := range memberProps {
+			properties[key] = prop
+		}
+		required = append(required, memberRequired...)
+	}
+	return properties, required
+}
+
+func pointerable(goType string) bool {
+	return !strings.HasPrefix(goType, "[]") &&
+		!strings.HasPrefix(goType, "map[") &&
+		!strings.HasPrefix(goType, "*") &&
+		goType != "json.RawMessage" &&
+		goType != "any"
+}
+
+func (g *typeGen) goType(s *Schema, hint string) string {
+	if s == nil {
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	}
+	if s.Ref != "" {
+		return exportedName(refName(s.Ref))
+	}
+	if len(s.AllOf) == 1 && len(s.Properties) == 0 {
+		return g.goType(s.AllOf[0], hint)
+	}
+
+	switch s.Type.Name {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			g.imports["time"] = true
+			return "time.Time"
+		case "byte", "binary":
+			return "[]byte"
+		}
+		return "string"
+	case "integer":
+		if s.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.goType(s.Items, hint+"Item")
+	case "object", "":
+		if isStruct(s) {
+			name := g.reserve(hint)
+			g.decls[name] = fmt.Sprintf("type %s %s\n", name, g.structBody(name, s))
+			return name
+		}
+		if len(s.AdditionalProperties) > 0 && string(s.AdditionalProperties) != "true" && string(s.AdditionalProperties) != "false" {
+			var value Schema
+			if err := json.Unmarshal(s.AdditionalProperties, &value); err == nil {
+				return "map[string]" + g.goType(&value, hint+"Value")
+			}
+		}
+		if s.Type.Name == "object" {
+			return "map[string]any"
+		}
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	}
+	return "any"
+}
+
+func (g *typeGen) source() string {
+	var b strings.Builder
+	for _, name := range g.order {
+		b.WriteString(g.decls[name])
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func writeComment(b *strings.Builder, text string) {
+	indent := text[:len(text)-len(strings.TrimLeft(text, "\t"))]
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		fmt.Fprintf(b, "%s// %s\n", indent, strings.TrimSpace(line))
+	}
+}
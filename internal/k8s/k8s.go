@@ -0,0 +1,388 @@
+// Package k8s renders Kubernetes manifests of a project from deploy/k8s/values.yaml and
+// the variables internal/config reads.
+package k8s
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/goccy/go-yaml"
+
+	"github.com/alwaysgolang/hippo-cli/internal/project"
+)
+
+const (
+	ValuesFile    = "deploy/k8s/values.yaml"
+	ManifestsFile = "deploy/k8s/manifests.yaml"
+
+	// graceMargin is added to SHUTDOWN_TIMEOUT so the kubelet does not kill the pod
+	// while it is still draining.
+	graceMargin            = 5
+	defaultShutdownTimeout = 30 * time.Second
+)
+
+// Values is what a project chooses about its deployment. Everything else is derived from
+// the code.
+type Values struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+	Image     string `yaml:"image"`
+	Replicas  int    `yaml:"replicas"`
+	Port      int    `yaml:"port"`
+	// Probe is "health" for the health subcommand of the binary or "http" for GET /api/ping.
+	Probe     string            `yaml:"probe"`
+	Resources Resources         `yaml:"resources"`
+	Env       map[string]any    `yaml:"env"`
+	Secrets   []string          `yaml:"secrets"`
+	Labels    map[string]string `yaml:"labels"`
+}
+
+type Resources struct {
+	Requests map[string]string `yaml:"requests"`
+	Limits   map[string]string `yaml:"limits"`
+}
+
+func ReadValues(path string) (*Values, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// a missing replicas key means one pod, not a Deployment scaled to zero
+	v := &Values{Replicas: 1}
+	if err := yaml.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return v, nil
+}
+
+var secretWords = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "DSN", "PRIVATE"}
+
+// IsSecret guesses from the name whether a variable belongs into the Secret.
+func IsSecret(name string) bool {
+	if strings.Contains(name, "API_KEY") {
+		return true
+	}
+	for _, word := range strings.Split(name, "_") {
+		if slices.Contains(secretWords, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// RunRender renders the manifests of the project in the working directory to out, "-"
+// meaning stdout. Secret values are only filled in on stdout, files get the keys alone.
+// The output is validated before anything is written.
+func RunRender(valuesPath, out string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	root, err := project.Root(wd)
+	if err != nil {
+		return err
+	}
+	if valuesPath == "" {
+		valuesPath = filepath.Join(root, ValuesFile)
+	}
+	if out == "" {
+		out = filepath.Join(root, ManifestsFile)
+	}
+
+	values, err := ReadValues(valuesPath)
+	if err != nil {
+		return err
+	}
+	manifests, warnings, err := Render(root, values, out == "-")
+	for _, w := range warnings {
+		color.Yellow("⚠ %s", w)
+	}
+	if err != nil {
+		return err
+	}
+
+	if out == "-" {
+		_, err = os.Stdout.Write(manifests)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(out), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, manifests, 0644); err != nil {
+		return err
+	}
+	color.Green("✔ %s", out)
+	if len(values.Secrets) > 0 {
+		color.Yellow("👉 Secret values are left empty in files, to fill them from the environment run: hippo k8s render --out -")
+	}
+	return nil
+}
+
+// Render builds the ConfigMap, Secret, Deployment and Service. With secretValues the
+// Secret is filled from the environment of the render, so CI can pipe it straight into
+// kubectl apply, and warnings name what is left empty. Without, it lists the keys only.
+func Render(root string, v *Values, secretValues bool) ([]byte, []string, error) {
+	vars, err := project.ConfigEnv(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	module, err := project.ModulePath(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	env, warnings, err := validate(v, vars)
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	labels := map[string]string{"app.kubernetes.io/name": v.Name}
+	for key, value := range v.Labels {
+		labels[key] = value
+	}
+	meta := func(name string) metadata {
+		return metadata{Name: name, Namespace: v.Namespace, Labels: labels}
+	}
+	selector := map[string]string{"app.kubernetes.io/name": v.Name}
+
+	secrets := map[string]string{}
+	var empty []string
+	for _, key := range v.Secrets {
+		if !secretValues {
+			secrets[key] = ""
+			continue
+		}
+		secrets[key] = os.Getenv(key)
+		if secrets[key] == "" {
+			empty = append(empty, key)
+		}
+	}
+	if len(empty) > 0 {
+		warnings = append(warnings, "secret values not set in the environment, rendered empty: "+strings.Join(empty, ", "))
+	}
+
+	shutdown := defaultShutdownTimeout
+	if raw := env["SHUTDOWN_TIMEOUT"]; raw != "" {
+		if shutdown, err = time.ParseDuration(raw); err != nil {
+			return nil, warnings, fmt.Errorf("env.SHUTDOWN_TIMEOUT: %w", err)
+		}
+	}
+
+	scheme := ""
+	if env["APPLICATION_HTTP_TLS_CERT_FILE"] != "" {
+		scheme = "HTTPS"
+	}
+	liveness := &probe{PeriodSeconds: 10, TimeoutSeconds: 5, FailureThreshold: 3}
+	if v.Probe == "http" {
+		liveness.HTTPGet = &httpGetAction{Path: "/api/ping", Port: "http", Scheme: scheme}
+	} else {
+		// the Dockerfile of the docker module names the binary after the module, not the deployment
+		liveness.Exec = &execAction{Command: []string{"/app/" + project.ServiceName(module), "health"}}
+	}
+	startup := *liveness
+	startup.PeriodSeconds, startup.FailureThreshold = 2, 30
+
+	docs := []any{
+		configMap{APIVersion: "v1", Kind: "ConfigMap", Metadata: meta(v.Name), Data: env},
+		secret{APIVersion: "v1", Kind: "Secret", Metadata: meta(v.Name), Type: "Opaque", StringData: secrets},
+		deployment{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Metadata:   meta(v.Name),
+			Spec: deploymentSpec{
+				Replicas: v.Replicas,
+				Selector: labelSelector{MatchLabels: selector},
+				Template: podTemplateSpec{
+					Metadata: metadata{Labels: labels},
+					Spec: podSpec{
+						TerminationGracePeriodSeconds: int(math.Ceil(shutdown.Seconds())) + graceMargin,
+						SecurityContext:               podSecurityContext{RunAsNonRoot: true},
+						Containers: []container{{
+							Name:  v.Name,
+							Image: v.Image,
+							Ports: []containerPort{{Name: "http", ContainerPort: v.Port}},
+							EnvFrom: []envFromSource{
+								{ConfigMapRef: &localObjectReference{Name: v.Name}},
+								{SecretRef: &localObjectReference{Name: v.Name}},
+							},
+							Resources:      resourceRequirements{Requests: v.Resources.Requests, Limits: v.Resources.Limits},
+							StartupProbe:   &startup,
+							LivenessProbe:  liveness,
+							ReadinessProbe: &probe{HTTPGet: &httpGetAction{Path: "/ready", Port: "http", Scheme: scheme}, PeriodSeconds: 5},
+							SecurityContext: containerSecurityContext{
+								AllowPrivilegeEscalation: false,
+								ReadOnlyRootFilesystem:   true,
+							},
+							// the admin socket of the health subcommand lives in /tmp
+							VolumeMounts: []volumeMount{{Name: "tmp", MountPath: "/tmp"}},
+						}},
+						Volumes: []volume{{Name: "tmp"}},
+					},
+				},
+			},
+		},
+		service{
+			APIVersion: "v1",
+			Kind:       "Service",
+			Metadata:   meta(v.Name),
+			Spec: serviceSpec{
+				Selector: selector,
+				Ports:    []servicePort{{Name: "http", Port: 80, TargetPort: "http"}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by hippo k8s render from " + ValuesFile + ", do not edit.\n")
+	if !secretValues && len(v.Secrets) > 0 {
+		buf.WriteString("# Secret values are empty, render with --out - to fill them from the environment.\n")
+	}
+	for i, doc := range docs {
+		data, err := yaml.Marshal(doc)
+		if err != nil {
+			return nil, warnings, err
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(data)
+	}
+	if err := validateOutput(buf.Bytes()); err != nil {
+		return nil, warnings, fmt.Errorf("rendered manifests are invalid: %w", err)
+	}
+	return buf.Bytes(), warnings, nil
+}
+
+var (
+	dnsLabel = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	envName  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// quantity is the format of resource.Quantity, e.g. 250m, 128Mi or 1.5
+	quantity = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]+|m|k|M|G|T|P|E|Ki|Mi|Gi|Ti|Pi|Ei)?$`)
+)
+
+// validate checks values against the configuration code and returns the ConfigMap data.
+func validate(v *Values, vars []project.EnvVar) (map[string]string, []string, error) {
+	var errs []error
+	var warnings []string
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if len(v.Name) > 63 || !dnsLabel.MatchString(v.Name) {
+		invalid("name %q must be a DNS label: lower case letters, digits and '-', at most 63 characters", v.Name)
+	}
+	if v.Namespace != "" && !dnsLabel.MatchString(v.Namespace) {
+		invalid("namespace %q must be a DNS label", v.Namespace)
+	}
+	if v.Image == "" {
+		invalid("image is required")
+	}
+	if v.Replicas < 0 {
+		invalid("replicas must not be negative")
+	}
+	if v.Port < 1 || v.Port > 65535 {
+		invalid("port %d is out of range", v.Port)
+	}
+	if v.Probe != "health" && v.Probe != "http" {
+		invalid("probe %q must be health or http", v.Probe)
+	}
+	for kind, resources := range map[string]map[string]string{"requests": v.Resources.Requests, "limits": v.Resources.Limits} {
+		for name, value := range resources {
+			if !quantity.MatchString(value) {
+				invalid("resources.%s.%s: %q is not a quantity", kind, name, value)
+			}
+		}
+	}
+
+	known := map[string]project.EnvVar{}
+	for _, ev := range vars {
+		known[ev.Name] = ev
+	}
+
+	env := make(map[string]string, len(v.Env)+1)
+	for key, raw := range v.Env {
+		value := ""
+		if raw != nil {
+			value = fmt.Sprint(raw)
+		}
+		env[key] = value
+	}
+	if port, ok := env["APPLICATION_HTTP_PORT"]; ok && port != strconv.Itoa(v.Port) {
+		invalid("env.APPLICATION_HTTP_PORT=%s differs from port %d", port, v.Port)
+	}
+	env["APPLICATION_HTTP_PORT"] = strconv.Itoa(v.Port)
+
+	for key, value := range env {
+		if !envName.MatchString(key) {
+			invalid("env.%s is not a valid variable name", key)
+			continue
+		}
+		if slices.Contains(v.Secrets, key) {
+			invalid("%s is listed in both env and secrets", key)
+		}
+		ev, ok := known[key]
+		if !ok {
+			// names built at runtime, e.g. CLIENT_<NAME>_BASE_URL, are not known statically
+			warnings = append(warnings, "env."+key+" is not read by "+project.ConfigDir+" by that name")
+			continue
+		}
+		if err := ev.Validate(value); err != nil {
+			invalid("env: %w", err)
+		}
+	}
+	for _, key := range v.Secrets {
+		if !envName.MatchString(key) {
+			invalid("secrets: %s is not a valid variable name", key)
+		}
+	}
+	for _, ev := range vars {
+		if _, inEnv := env[ev.Name]; ev.Required && !inEnv && !slices.Contains(v.Secrets, ev.Name) {
+			invalid("%s is required by %s, set it in env or secrets", ev.Name, project.ConfigDir)
+		}
+	}
+
+	if env["APPLICATION_HTTP_SOCKET"] != "" {
+		invalid("APPLICATION_HTTP_SOCKET is set, probes and the Service need a TCP port")
+	}
+	if raw, ok := env["SHUTDOWN_TIMEOUT"]; ok && raw != "" {
+		timeout, err1 := time.ParseDuration(raw)
+		delay, err2 := time.ParseDuration(cmp.Or(env["SHUTDOWN_PRE_STOP_DELAY"], "0s"))
+		if err1 == nil && err2 == nil && delay >= timeout {
+			invalid("SHUTDOWN_PRE_STOP_DELAY %s must be shorter than SHUTDOWN_TIMEOUT %s", delay, timeout)
+		}
+	}
+
+	slices.Sort(warnings)
+	slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+	return env, warnings, errors.Join(errs...)
+}
+
+// validateOutput parses the rendered documents back, as kubectl would before sending them.
+func validateOutput(data []byte) error {
+	for i, doc := range bytes.Split(data, []byte("\n---\n")) {
+		var object struct {
+			APIVersion string `yaml:"apiVersion"`
+			Kind       string `yaml:"kind"`
+			Metadata   struct {
+				Name string `yaml:"name"`
+			} `yaml:"metadata"`
+		}
+		if err := yaml.Unmarshal(doc, &object); err != nil {
+			return fmt.Errorf("document %d: %w", i+1, err)
+		}
+		if object.APIVersion == "" || object.Kind == "" || object.Metadata.Name == "" {
+			return fmt.Errorf("document %d: apiVersion, kind and metadata.name are required", i+1)
+		}
+	}
+	return nil
+}
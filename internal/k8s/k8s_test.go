@@ -0,0 +1,270 @@
+package k8s
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+)
+
+// writeProject lays out a module whose configuration reads a required WORKERS, the
+// shutdown timeouts and the TLS and socket settings of the HTTP server.
+func writeProject(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/shop-api\n\ngo 1.26\n",
+		"internal/config/config.go": `package config
+
+func load() {
+	mustInt("APPLICATION_HTTP_PORT")
+	mustInt("WORKERS")
+	getDuration("SHUTDOWN_TIMEOUT")
+	getDuration("SHUTDOWN_PRE_STOP_DELAY")
+	getString("APPLICATION_HTTP_SOCKET")
+	getString("APPLICATION_HTTP_TLS_CERT_FILE")
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func validValues() *Values {
+	return &Values{
+		Name:     "shop",
+		Image:    "shop:1.0",
+		Replicas: 1,
+		Port:     8080,
+		Probe:    "health",
+		Env:      map[string]any{"WORKERS": 4},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	root := writeProject(t)
+
+	tests := []struct {
+		name     string
+		edit     func(v *Values)
+		err      string
+		warnings []string
+	}{
+		{name: "valid", edit: func(*Values) {}},
+		{name: "name", edit: func(v *Values) { v.Name = "Shop_API" }, err: `name "Shop_API" must be a DNS label`},
+		{name: "namespace", edit: func(v *Values) { v.Namespace = "Prod" }, err: `namespace "Prod" must be a DNS label`},
+		{name: "image", edit: func(v *Values) { v.Image = "" }, err: "image is required"},
+		{name: "replicas", edit: func(v *Values) { v.Replicas = -1 }, err: "replicas must not be negative"},
+		{name: "port", edit: func(v *Values) { v.Port = 70000 }, err: "port 70000 is out of range"},
+		{name: "probe", edit: func(v *Values) { v.Probe = "tcp" }, err: `probe "tcp" must be health or http`},
+		{name: "quantity", edit: func(v *Values) {
+			v.Resources.Limits = map[string]string{"memory": "lots"}
+		}, err: `resources.limits.memory: "lots" is not a quantity`},
+		{name: "kind", edit: func(v *Values) { v.Env["WORKERS"] = "many" }, err: "WORKERS=many is not a valid int"},
+		{name: "port in env", edit: func(v *Values) {
+			v.Env["APPLICATION_HTTP_PORT"] = 9000
+		}, err: "env.APPLICATION_HTTP_PORT=9000 differs from port 8080"},
+		{name: "env and secrets", edit: func(v *Values) {
+			v.Secrets = []string{"WORKERS"}
+		}, err: "WORKERS is listed in both env and secrets"},
+		{name: "required", edit: func(v *Values) { delete(v.Env, "WORKERS") }, err: "WORKERS is required by internal/config"},
+		{name: "required in secrets", edit: func(v *Values) {
+			delete(v.Env, "WORKERS")
+			v.Secrets = []string{"WORKERS"}
+		}},
+		{name: "socket", edit: func(v *Values) {
+			v.Env["APPLICATION_HTTP_SOCKET"] = "/tmp/app.sock"
+		}, err: "APPLICATION_HTTP_SOCKET is set"},
+		{name: "pre-stop delay", edit: func(v *Values) {
+			v.Env["SHUTDOWN_TIMEOUT"] = "10s"
+			v.Env["SHUTDOWN_PRE_STOP_DELAY"] = "10s"
+		}, err: "SHUTDOWN_PRE_STOP_DELAY 10s must be shorter than SHUTDOWN_TIMEOUT 10s"},
+		{name: "unknown variable", edit: func(v *Values) {
+			v.Env["CLIENT_BILLING_BASE_URL"] = "http://billing"
+		}, warnings: []string{"env.CLIENT_BILLING_BASE_URL is not read by internal/config by that name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validValues()
+			tt.edit(v)
+
+			_, warnings, err := Render(root, v, false)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Fatalf("error %v, want one containing %q", err, tt.err)
+			}
+			if !slices.Equal(warnings, tt.warnings) {
+				t.Errorf("warnings %q, want %q", warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	root := writeProject(t)
+
+	tests := []struct {
+		name         string
+		edit         func(v *Values)
+		secretValues bool
+		check        func(t *testing.T, m manifests)
+	}{
+		{
+			name: "health probe runs the binary of the module",
+			edit: func(*Values) {},
+			check: func(t *testing.T, m manifests) {
+				c := m.deployment.Spec.Template.Spec.Containers[0]
+				if got := c.LivenessProbe.Exec.Command; !slices.Equal(got, []string{"/app/shop-api", "health"}) {
+					t.Errorf("liveness command %q", got)
+				}
+				if c.StartupProbe.Exec == nil || c.StartupProbe.FailureThreshold != 30 {
+					t.Errorf("startup probe %+v", c.StartupProbe)
+				}
+				if got := m.deployment.Spec.Template.Spec.TerminationGracePeriodSeconds; got != 35 {
+					t.Errorf("grace period %d, want the default timeout plus the margin", got)
+				}
+				if got := m.configMap.Data["APPLICATION_HTTP_PORT"]; got != "8080" {
+					t.Errorf("APPLICATION_HTTP_PORT=%q", got)
+				}
+			},
+		},
+		{
+			name: "http probe over TLS",
+			edit: func(v *Values) {
+				v.Probe = "http"
+				v.Env["APPLICATION_HTTP_TLS_CERT_FILE"] = "/certs/tls.crt"
+			},
+			check: func(t *testing.T, m manifests) {
+				c := m.deployment.Spec.Template.Spec.Containers[0]
+				if get := c.LivenessProbe.HTTPGet; get == nil || get.Path != "/api/ping" || get.Scheme != "HTTPS" {
+					t.Errorf("liveness probe %+v", c.LivenessProbe)
+				}
+				if get := c.ReadinessProbe.HTTPGet; get.Path != "/ready" || get.Scheme != "HTTPS" {
+					t.Errorf("readiness probe %+v", get)
+				}
+			},
+		},
+		{
+			name: "shutdown timeout",
+			edit: func(v *Values) { v.Env["SHUTDOWN_TIMEOUT"] = "20.5s" },
+			check: func(t *testing.T, m manifests) {
+				if got := m.deployment.Spec.Template.Spec.TerminationGracePeriodSeconds; got != 26 {
+					t.Errorf("grace period %d, want 26", got)
+				}
+			},
+		},
+		{
+			name: "secret keys only",
+			edit: func(v *Values) { v.Secrets = []string{"DB_PASSWORD"} },
+			check: func(t *testing.T, m manifests) {
+				if value, ok := m.secret.StringData["DB_PASSWORD"]; !ok || value != "" {
+					t.Errorf("stringData %v, want the key without a value", m.secret.StringData)
+				}
+				if !bytes.Contains(m.raw, []byte("# Secret values are empty")) {
+					t.Error("missing the hint on empty secret values")
+				}
+			},
+		},
+		{
+			name:         "secret values",
+			edit:         func(v *Values) { v.Secrets = []string{"DB_PASSWORD"} },
+			secretValues: true,
+			check: func(t *testing.T, m manifests) {
+				if got := m.secret.StringData["DB_PASSWORD"]; got != "s3cret" {
+					t.Errorf("DB_PASSWORD=%q", got)
+				}
+			},
+		},
+		{
+			name: "labels and namespace",
+			edit: func(v *Values) {
+				v.Namespace = "prod"
+				v.Replicas = 3
+				v.Labels = map[string]string{"team": "checkout"}
+			},
+			check: func(t *testing.T, m manifests) {
+				meta := m.deployment.Metadata
+				if meta.Namespace != "prod" || meta.Labels["team"] != "checkout" || meta.Labels["app.kubernetes.io/name"] != "shop" {
+					t.Errorf("metadata %+v", meta)
+				}
+				if m.deployment.Spec.Replicas != 3 {
+					t.Errorf("replicas %d", m.deployment.Spec.Replicas)
+				}
+			},
+		},
+	}
+
+	t.Setenv("DB_PASSWORD", "s3cret")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validValues()
+			tt.edit(v)
+
+			out, _, err := Render(root, v, tt.secretValues)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, parseManifests(t, out))
+		})
+	}
+}
+
+func TestReadValuesDefaultsReplicas(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "values.yaml")
+	if err := os.WriteFile(path, []byte("name: shop\nimage: shop:1.0\nport: 8080\nprobe: health\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	v, err := ReadValues(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Replicas != 1 {
+		t.Errorf("replicas %d, want 1 when values.yaml omits it", v.Replicas)
+	}
+
+	if err := os.WriteFile(path, []byte("replicas: 0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if v, err = ReadValues(path); err != nil || v.Replicas != 0 {
+		t.Errorf("explicit replicas: 0 read as %d, %v", v.Replicas, err)
+	}
+}
+
+type manifests struct {
+	raw        []byte
+	configMap  configMap
+	secret     secret
+	deployment deployment
+}
+
+func parseManifests(t *testing.T, data []byte) manifests {
+	t.Helper()
+
+	m := manifests{raw: data}
+	docs := bytes.Split(data, []byte("\n---\n"))
+	if len(docs) != 4 {
+		t.Fatalf("got %d documents, want 4:\n%s", len(docs), data)
+	}
+	for i, target := range []any{&m.configMap, &m.secret, &m.deployment} {
+		if err := yaml.Unmarshal(docs[i], target); err != nil {
+			t.Fatalf("document %d: %v", i+1, err)
+		}
+	}
+	return m
+}
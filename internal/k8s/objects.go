@@ -0,0 +1,140 @@
+package k8s
+
+// The subset of the Kubernetes API the rendered manifests use.
+
+type metadata struct {
+	Name      string            `yaml:"name,omitempty"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty"`
+}
+
+type configMap struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   metadata          `yaml:"metadata"`
+	Data       map[string]string `yaml:"data"`
+}
+
+type secret struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   metadata          `yaml:"metadata"`
+	Type       string            `yaml:"type"`
+	StringData map[string]string `yaml:"stringData"`
+}
+
+type deployment struct {
+	APIVersion string         `yaml:"apiVersion"`
+	Kind       string         `yaml:"kind"`
+	Metadata   metadata       `yaml:"metadata"`
+	Spec       deploymentSpec `yaml:"spec"`
+}
+
+type deploymentSpec struct {
+	Replicas int             `yaml:"replicas"`
+	Selector labelSelector   `yaml:"selector"`
+	Template podTemplateSpec `yaml:"template"`
+}
+
+type labelSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+type podTemplateSpec struct {
+	Metadata metadata `yaml:"metadata"`
+	Spec     podSpec  `yaml:"spec"`
+}
+
+type podSpec struct {
+	TerminationGracePeriodSeconds int                `yaml:"terminationGracePeriodSeconds"`
+	SecurityContext               podSecurityContext `yaml:"securityContext"`
+	Containers                    []container        `yaml:"containers"`
+	Volumes                       []volume           `yaml:"volumes,omitempty"`
+}
+
+type podSecurityContext struct {
+	RunAsNonRoot bool `yaml:"runAsNonRoot"`
+}
+
+type container struct {
+	Name            string                   `yaml:"name"`
+	Image           string                   `yaml:"image"`
+	Ports           []containerPort          `yaml:"ports,omitempty"`
+	EnvFrom         []envFromSource          `yaml:"envFrom,omitempty"`
+	Resources       resourceRequirements     `yaml:"resources,omitempty"`
+	StartupProbe    *probe                   `yaml:"startupProbe,omitempty"`
+	LivenessProbe   *probe                   `yaml:"livenessProbe,omitempty"`
+	ReadinessProbe  *probe                   `yaml:"readinessProbe,omitempty"`
+	SecurityContext containerSecurityContext `yaml:"securityContext"`
+	VolumeMounts    []volumeMount            `yaml:"volumeMounts,omitempty"`
+}
+
+type containerPort struct {
+	Name          string `yaml:"name"`
+	ContainerPort int    `yaml:"containerPort"`
+}
+
+type envFromSource struct {
+	ConfigMapRef *localObjectReference `yaml:"configMapRef,omitempty"`
+	SecretRef    *localObjectReference `yaml:"secretRef,omitempty"`
+}
+
+type localObjectReference struct {
+	Name string `yaml:"name"`
+}
+
+type resourceRequirements struct {
+	Requests map[string]string `yaml:"requests,omitempty"`
+	Limits   map[string]string `yaml:"limits,omitempty"`
+}
+
+type probe struct {
+	Exec             *execAction    `yaml:"exec,omitempty"`
+	HTTPGet          *httpGetAction `yaml:"httpGet,omitempty"`
+	PeriodSeconds    int            `yaml:"periodSeconds,omitempty"`
+	TimeoutSeconds   int            `yaml:"timeoutSeconds,omitempty"`
+	FailureThreshold int            `yaml:"failureThreshold,omitempty"`
+}
+
+type execAction struct {
+	Command []string `yaml:"command"`
+}
+
+type httpGetAction struct {
+	Path   string `yaml:"path"`
+	Port   string `yaml:"port"`
+	Scheme string `yaml:"scheme,omitempty"`
+}
+
+type containerSecurityContext struct {
+	AllowPrivilegeEscalation bool `yaml:"allowPrivilegeEscalation"`
+	ReadOnlyRootFilesystem   bool `yaml:"readOnlyRootFilesystem"`
+}
+
+type volume struct {
+	Name     string   `yaml:"name"`
+	EmptyDir struct{} `yaml:"emptyDir"`
+}
+
+type volumeMount struct {
+	Name      string `yaml:"name"`
+	MountPath string `yaml:"mountPath"`
+}
+
+type service struct {
+	APIVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	Metadata   metadata    `yaml:"metadata"`
+	Spec       serviceSpec `yaml:"spec"`
+}
+
+type serviceSpec struct {
+	Selector map[string]string `yaml:"selector"`
+	Ports    []servicePort     `yaml:"ports"`
+}
+
+type servicePort struct {
+	Name       string `yaml:"name"`
+	Port       int    `yaml:"port"`
+	TargetPort string `yaml:"targetPort"`
+}
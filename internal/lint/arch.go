@@ -0,0 +1,278 @@
+// Package lint holds the project checks behind hippo lint.
+package lint
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/alwaysgolang/hippo-cli/internal/project"
+)
+
+// Violation is an import of a layer the importing one must not depend on.
+type Violation struct {
+	// Pos is the import declaration in the offending package.
+	Pos  token.Position
+	From string
+	To   string
+	// Chain holds the import paths from the imported package down to the forbidden one,
+	// it has a single element for direct imports.
+	Chain []string
+}
+
+func (v Violation) String() string {
+	if len(v.Chain) == 1 {
+		return fmt.Sprintf("%s: %s must not import %s: %s", v.Pos, v.From, v.To, v.Chain[0])
+	}
+	return fmt.Sprintf("%s: %s must not import %s: %s", v.Pos, v.From, v.To, strings.Join(v.Chain, " -> "))
+}
+
+// RunArch checks the project in the working directory against the layers in hippo.json,
+// or the default ones, and prints every violation.
+func RunArch() error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	root, err := project.Root(wd)
+	if err != nil {
+		return err
+	}
+	module, err := project.ModulePath(root)
+	if err != nil {
+		return err
+	}
+	manifest, err := project.ReadManifest(root)
+	if err != nil {
+		return err
+	}
+	arch := manifest.Arch
+	if arch == nil || len(arch.Layers) == 0 {
+		arch = project.DefaultArch()
+	}
+
+	violations, err := CheckArch(root, module, arch)
+	if err != nil {
+		return err
+	}
+	for _, v := range violations {
+		if rel, err := filepath.Rel(wd, v.Pos.Filename); err == nil {
+			v.Pos.Filename = rel
+		}
+		fmt.Println(v)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%d architecture violation(s)", len(violations))
+	}
+	color.Green("✔ Architecture check passed")
+	return nil
+}
+
+// CheckArch loads every package of the module at root and reports imports crossing the
+// layers in arch. A layer reaching a forbidden one through packages outside of any layer,
+// e.g. pkg/..., is reported as well.
+func CheckArch(root, module string, arch *project.ArchConfig) ([]Violation, error) {
+	rules, err := newLayerRules(module, arch)
+	if err != nil {
+		return nil, err
+	}
+
+	pkgs, err := packages.Load(&packages.Config{
+		Dir:   root,
+		Mode:  packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedSyntax,
+		Tests: true,
+	}, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("load packages: %w", err)
+	}
+
+	// byPath is the import graph of the production code, transitive imports are followed
+	// there. checked holds one variant of every package: the one compiled with its test
+	// files when there is one, so imports in tests are reported too, but only once.
+	byPath := make(map[string]*packages.Package, len(pkgs))
+	checked := make(map[string]*packages.Package, len(pkgs))
+	for _, pkg := range pkgs {
+		for _, e := range pkg.Errors {
+			if e.Kind != packages.TypeError {
+				return nil, fmt.Errorf("load %s: %s", pkg.PkgPath, e)
+			}
+		}
+		if strings.HasSuffix(pkg.PkgPath, ".test") {
+			// the generated main package of go test
+			continue
+		}
+		if pkg.ID == pkg.PkgPath {
+			byPath[pkg.PkgPath] = pkg
+		}
+		if prev, ok := checked[pkg.PkgPath]; !ok || len(pkg.GoFiles) > len(prev.GoFiles) {
+			checked[pkg.PkgPath] = pkg
+		}
+	}
+
+	var violations []Violation
+	for _, pkg := range checked {
+		// external tests, package foo_test, belong to the layer of foo
+		from := rules.layerOf(strings.TrimSuffix(pkg.PkgPath, "_test"))
+		if from == nil {
+			continue
+		}
+		for _, imp := range sortedImports(pkg) {
+			if !isLocal(module, imp) {
+				continue
+			}
+			var chains [][]string
+			if to := rules.layerOf(imp); to != nil {
+				if !rules.allowed(from, to) {
+					chains = append(chains, []string{imp})
+				}
+			} else {
+				chains = rules.reach(from, imp, byPath)
+			}
+			for _, chain := range chains {
+				violations = append(violations, Violation{
+					Pos:   importPos(pkg, imp),
+					From:  from.Name,
+					To:    rules.layerOf(chain[len(chain)-1]).Name,
+					Chain: chain,
+				})
+			}
+		}
+	}
+
+	slices.SortFunc(violations, func(a, b Violation) int {
+		if c := strings.Compare(a.Pos.Filename, b.Pos.Filename); c != 0 {
+			return c
+		}
+		if a.Pos.Line != b.Pos.Line {
+			return a.Pos.Line - b.Pos.Line
+		}
+		return slices.Compare(a.Chain, b.Chain)
+	})
+	return violations, nil
+}
+
+type layerRules struct {
+	module string
+	layers []project.Layer
+}
+
+func newLayerRules(module string, arch *project.ArchConfig) (*layerRules, error) {
+	names := map[string]bool{}
+	for _, l := range arch.Layers {
+		if l.Name == "" || l.Path == "" {
+			return nil, fmt.Errorf("arch: every layer needs a name and a path")
+		}
+		if names[l.Name] {
+			return nil, fmt.Errorf("arch: layer %q is defined twice", l.Name)
+		}
+		names[l.Name] = true
+	}
+	for _, l := range arch.Layers {
+		for _, allowed := range l.Allow {
+			if !names[allowed] {
+				return nil, fmt.Errorf("arch: layer %q allows unknown layer %q", l.Name, allowed)
+			}
+		}
+	}
+	return &layerRules{module: module, layers: arch.Layers}, nil
+}
+
+// layerOf returns the layer with the longest path containing pkgPath.
+func (r *layerRules) layerOf(pkgPath string) *project.Layer {
+	var best *project.Layer
+	for i, l := range r.layers {
+		prefix := r.module + "/" + strings.Trim(filepath.ToSlash(l.Path), "/")
+		if pkgPath != prefix && !strings.HasPrefix(pkgPath, prefix+"/") {
+			continue
+		}
+		if best == nil || len(l.Path) > len(best.Path) {
+			best = &r.layers[i]
+		}
+	}
+	return best
+}
+
+func (r *layerRules) allowed(from, to *project.Layer) bool {
+	if from.Name == to.Name {
+		return true
+	}
+	if from.Allow != nil {
+		return slices.Contains(from.Allow, to.Name)
+	}
+	return slices.IndexFunc(r.layers, func(l project.Layer) bool { return l.Name == to.Name }) <
+		slices.IndexFunc(r.layers, func(l project.Layer) bool { return l.Name == from.Name })
+}
+
+// reach walks the packages outside of any layer starting at start and returns the
+// shortest import chain to every package of a layer from must not depend on.
+func (r *layerRules) reach(from *project.Layer, start string, byPath map[string]*packages.Package) [][]string {
+	parent := map[string]string{start: ""}
+	queue := []string{start}
+	var chains [][]string
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		pkg, ok := byPath[current]
+		if !ok {
+			continue
+		}
+		for _, imp := range sortedImports(pkg) {
+			if _, seen := parent[imp]; seen || !isLocal(r.module, imp) {
+				continue
+			}
+			parent[imp] = current
+			if to := r.layerOf(imp); to != nil {
+				if !r.allowed(from, to) {
+					chains = append(chains, chainTo(parent, imp))
+				}
+				continue
+			}
+			queue = append(queue, imp)
+		}
+	}
+	return chains
+}
+
+func chainTo(parent map[string]string, last string) []string {
+	var chain []string
+	for p := last; p != ""; p = parent[p] {
+		chain = append(chain, p)
+	}
+	slices.Reverse(chain)
+	return chain
+}
+
+func sortedImports(pkg *packages.Package) []string {
+	paths := make([]string, 0, len(pkg.Imports))
+	for path := range pkg.Imports {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+func isLocal(module, pkgPath string) bool {
+	return pkgPath == module || strings.HasPrefix(pkgPath, module+"/")
+}
+
+// importPos finds the import declaration of path in pkg, whatever alias or grouping it uses.
+func importPos(pkg *packages.Package, path string) token.Position {
+	for _, file := range pkg.Syntax {
+		for _, spec := range file.Imports {
+			if p, err := strconv.Unquote(spec.Path.Value); err == nil && p == path {
+				return pkg.Fset.Position(spec.Pos())
+			}
+		}
+	}
+	if len(pkg.GoFiles) > 0 {
+		return token.Position{Filename: pkg.GoFiles[0]}
+	}
+	return token.Position{Filename: pkg.PkgPath}
+}
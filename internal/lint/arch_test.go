@@ -0,0 +1,146 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/alwaysgolang/hippo-cli/internal/project"
+)
+
+const testModule = "example.com/app"
+
+// writeModule lays out a module with a usecase and an adapter package next to files.
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	all := map[string]string{
+		"go.mod":                      "module " + testModule + "\n\ngo 1.22\n",
+		"internal/domain/order.go":    "package domain\n\ntype Order struct{}\n",
+		"internal/usecase/usecase.go": "package usecase\n\nconst Name = \"usecase\"\n",
+		"internal/adapter/adapter.go": "package adapter\n\nconst Name = \"adapter\"\n",
+	}
+	for name, content := range files {
+		all[name] = content
+	}
+	for name, content := range all {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+type wantViolation struct {
+	file  string
+	line  int
+	from  string
+	to    string
+	chain []string
+}
+
+func TestCheckArch(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []wantViolation
+	}{
+		{
+			name: "direct import",
+			files: map[string]string{
+				"internal/domain/direct.go": "package domain\n\nimport \"example.com/app/internal/usecase\"\n\nvar _ = usecase.Name\n",
+			},
+			want: []wantViolation{
+				{"internal/domain/direct.go", 3, "domain", "usecase", []string{testModule + "/internal/usecase"}},
+			},
+		},
+		{
+			name: "aliased import",
+			files: map[string]string{
+				"internal/domain/aliased.go": "package domain\n\nimport uc \"example.com/app/internal/usecase\"\n\nvar _ = uc.Name\n",
+			},
+			want: []wantViolation{
+				{"internal/domain/aliased.go", 3, "domain", "usecase", []string{testModule + "/internal/usecase"}},
+			},
+		},
+		{
+			name: "multi-line import block",
+			files: map[string]string{
+				"internal/domain/grouped.go": "package domain\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/app/internal/adapter\"\n)\n\nvar _ = fmt.Sprint(adapter.Name)\n",
+			},
+			want: []wantViolation{
+				{"internal/domain/grouped.go", 6, "domain", "adapter", []string{testModule + "/internal/adapter"}},
+			},
+		},
+		{
+			name: "transitive through pkg",
+			files: map[string]string{
+				"pkg/helper/helper.go":     "package helper\n\nimport \"example.com/app/internal/adapter\"\n\nvar Name = adapter.Name\n",
+				"internal/domain/uses.go":  "package domain\n\nimport \"example.com/app/pkg/helper\"\n\nvar _ = helper.Name\n",
+				"internal/usecase/uses.go": "package usecase\n\nimport \"example.com/app/pkg/helper\"\n\nvar _ = helper.Name\n",
+			},
+			want: []wantViolation{
+				{"internal/domain/uses.go", 3, "domain", "adapter", []string{testModule + "/pkg/helper", testModule + "/internal/adapter"}},
+				{"internal/usecase/uses.go", 3, "usecase", "adapter", []string{testModule + "/pkg/helper", testModule + "/internal/adapter"}},
+			},
+		},
+		{
+			name: "test files",
+			files: map[string]string{
+				"internal/domain/order_test.go": "package domain\n\nimport \"example.com/app/internal/usecase\"\n\nvar _ = usecase.Name\n",
+				"internal/domain/xtest_test.go": "package domain_test\n\nimport (\n\t\"example.com/app/internal/adapter\"\n\t\"example.com/app/internal/domain\"\n)\n\nvar _ = adapter.Name\nvar _ = domain.Order{}\n",
+			},
+			want: []wantViolation{
+				{"internal/domain/order_test.go", 3, "domain", "usecase", []string{testModule + "/internal/usecase"}},
+				{"internal/domain/xtest_test.go", 4, "domain", "adapter", []string{testModule + "/internal/adapter"}},
+			},
+		},
+		{
+			name: "allowed imports",
+			files: map[string]string{
+				"internal/usecase/order.go":   "package usecase\n\nimport \"example.com/app/internal/domain\"\n\nvar _ = domain.Order{}\n",
+				"internal/adapter/handler.go": "package adapter\n\nimport \"example.com/app/internal/usecase\"\n\nvar _ = usecase.Name\n",
+			},
+		},
+		{
+			name: "test-only dependencies of pkg",
+			files: map[string]string{
+				"pkg/helper/helper.go":      "package helper\n\nconst Name = \"helper\"\n",
+				"pkg/helper/helper_test.go": "package helper\n\nimport \"example.com/app/internal/adapter\"\n\nvar _ = adapter.Name\n",
+				"internal/domain/uses.go":   "package domain\n\nimport \"example.com/app/pkg/helper\"\n\nvar _ = helper.Name\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := writeModule(t, tt.files)
+
+			violations, err := CheckArch(root, testModule, project.DefaultArch())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(violations) != len(tt.want) {
+				t.Fatalf("got %d violation(s), want %d: %v", len(violations), len(tt.want), violations)
+			}
+			for i, want := range tt.want {
+				got := violations[i]
+				rel, _ := filepath.Rel(root, got.Pos.Filename)
+				if filepath.ToSlash(rel) != want.file || got.Pos.Line != want.line {
+					t.Errorf("violation %d at %s:%d, want %s:%d", i, rel, got.Pos.Line, want.file, want.line)
+				}
+				if got.From != want.from || got.To != want.to || !slices.Equal(got.Chain, want.chain) {
+					t.Errorf("violation %d is %s -> %s via %v, want %s -> %s via %v",
+						i, got.From, got.To, got.Chain, want.from, want.to, want.chain)
+				}
+			}
+		})
+	}
+}
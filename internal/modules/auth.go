@@ -0,0 +1,68 @@
+package modules
+
+import (
+	"github.com/fatih/color"
+
+	"github.com/alwaysgolang/hippo-cli/internal/project"
+)
+
+func init() {
+	register(&Module{
+		Name:        "auth",
+		Description: "JWT (JWKS/OIDC) and API key authentication middleware",
+		Install:     installAuth,
+		Providers:   true,
+		Hints: []string{
+			"Set AUTH_JWT_ISSUER (and AUTH_JWT_AUDIENCE) or AUTH_API_KEY_<NAME>",
+			`Protect routes: api.Group("/admin", s.Auth.Middleware(), auth.RequireRoles("admin"))`,
+		},
+	})
+}
+
+const authProviders = `// AuthSet verifies credentials configured through AUTH_* variables.
+var AuthSet = wire.NewSet(
+	ProvideAuthConfig,
+	auth.NewAuthenticator,
+)
+
+func ProvideAuthConfig(cfg *config.Config) *auth.Config { return &cfg.Auth }`
+
+func installAuth(p *Project) error {
+	authImport := p.Module + "/pkg/auth"
+
+	if err := p.Patch("internal/config/entities.go", func(src []byte) ([]byte, error) {
+		src, err := project.AddImport(src, "", authImport)
+		if err != nil {
+			return nil, err
+		}
+		return insertOnce(src, "auth.Config", "type Config struct {", "\tAuth auth.Config\n")
+	}); err != nil {
+		return err
+	}
+
+	if err := p.Patch("internal/config/config.go", func(src []byte) ([]byte, error) {
+		return insertOnce(src, "loadAuth()", "cfg := Config{", "\tAuth: loadAuth(),\n")
+	}); err != nil {
+		return err
+	}
+
+	if err := project.AddSet(p.Root, "AuthSet", authProviders, map[string]string{authImport: ""}); err != nil {
+		return err
+	}
+	color.Green("✔ %s", project.ProvidersFile)
+
+	return p.Patch("internal/infrastructure/http/server.go", func(src []byte) ([]byte, error) {
+		src, err := project.AddImport(src, "", authImport)
+		if err != nil {
+			return nil, err
+		}
+		if src, err = insertOnce(src, "*auth.Authenticator\n", "type Server struct {", "\tAuth *auth.Authenticator\n"); err != nil {
+			return nil, err
+		}
+		if src, err = insertOnce(src, "authenticator *auth.Authenticator", "func NewServer(", "\tauthenticator *auth.Authenticator,\n"); err != nil {
+			return nil, err
+		}
+		// gofmt aligns the key, so the marker is the value alone
+		return insertOnce(src, " authenticator,\n", "server := &Server{", "\tAuth: authenticator,\n")
+	})
+}
@@ -0,0 +1,207 @@
+package modules
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/joho/godotenv"
+	"golang.org/x/mod/modfile"
+
+	"github.com/alwaysgolang/hippo-cli/internal/project"
+)
+
+func init() {
+	register(&Module{
+		Name:        "docker",
+		Description: "multi-stage Dockerfile and docker-compose.yml with the services the project uses",
+		Data:        dockerData,
+		Install:     installDocker,
+		Hints: []string{
+			"Build: docker build --build-arg VERSION=$(git describe --tags --always) --build-arg COMMIT=$(git rev-parse HEAD) .",
+			"Run everything: docker compose up --build",
+		},
+	})
+}
+
+// composeService is a dependency started next to the service in docker-compose.yml. It is
+// added when the project has a hippo module of the same name or requires one of Requires.
+type composeService struct {
+	Name     string
+	Requires []string
+	// Definition is the compose YAML of the service, indented to sit under services.
+	Definition string
+	Volume     string
+}
+
+var composeServices = []composeService{
+	{
+		Name:     "postgres",
+		Requires: []string{"github.com/jackc/pgx", "github.com/lib/pq", "gorm.io/driver/postgres"},
+		Definition: `  postgres:
+    image: postgres:17-alpine
+    environment:
+      POSTGRES_USER: ${POSTGRES_USER:-app}
+      POSTGRES_PASSWORD: ${POSTGRES_PASSWORD:-app}
+      POSTGRES_DB: ${POSTGRES_DB:-app}
+    ports:
+      - "5432:5432"
+    volumes:
+      - postgres-data:/var/lib/postgresql/data
+    healthcheck:
+      test: ["CMD-SHELL", "pg_isready -U $${POSTGRES_USER:-app}"]
+      interval: 5s
+      timeout: 3s
+      retries: 10`,
+		Volume: "postgres-data",
+	},
+	{
+		Name:     "redis",
+		Requires: []string{"github.com/redis/go-redis", "github.com/go-redis/redis", "github.com/redis/rueidis"},
+		Definition: `  redis:
+    image: redis:7-alpine
+    ports:
+      - "6379:6379"
+    volumes:
+      - redis-data:/data
+    healthcheck:
+      test: ["CMD", "redis-cli", "ping"]
+      interval: 5s
+      timeout: 3s
+      retries: 10`,
+		Volume: "redis-data",
+	},
+	{
+		Name:     "kafka",
+		Requires: []string{"github.com/segmentio/kafka-go", "github.com/IBM/sarama", "github.com/twmb/franz-go"},
+		Definition: `  kafka:
+    image: apache/kafka:3.9.0
+    environment:
+      KAFKA_NODE_ID: 1
+      KAFKA_PROCESS_ROLES: broker,controller
+      KAFKA_LISTENERS: PLAINTEXT://:9092,CONTROLLER://:9093
+      KAFKA_ADVERTISED_LISTENERS: PLAINTEXT://kafka:9092
+      KAFKA_CONTROLLER_LISTENER_NAMES: CONTROLLER
+      KAFKA_CONTROLLER_QUORUM_VOTERS: 1@kafka:9093
+      KAFKA_OFFSETS_TOPIC_REPLICATION_FACTOR: 1
+    ports:
+      - "9092:9092"
+    healthcheck:
+      test: ["CMD-SHELL", "/opt/kafka/bin/kafka-topics.sh --bootstrap-server localhost:9092 --list"]
+      interval: 10s
+      timeout: 10s
+      retries: 10`,
+	},
+	{
+		Name:     "rabbitmq",
+		Requires: []string{"github.com/rabbitmq/amqp091-go", "github.com/streadway/amqp"},
+		Definition: `  rabbitmq:
+    image: rabbitmq:4-management-alpine
+    ports:
+      - "5672:5672"
+      - "15672:15672"
+    volumes:
+      - rabbitmq-data:/var/lib/rabbitmq
+    healthcheck:
+      test: ["CMD", "rabbitmq-diagnostics", "-q", "ping"]
+      interval: 10s
+      timeout: 5s
+      retries: 10`,
+		Volume: "rabbitmq-data",
+	},
+	{
+		Name:     "nats",
+		Requires: []string{"github.com/nats-io/nats.go"},
+		Definition: `  nats:
+    image: nats:2-alpine
+    command: ["-js", "-m", "8222"]
+    ports:
+      - "4222:4222"
+    healthcheck:
+      test: ["CMD", "wget", "-q", "-O", "-", "http://localhost:8222/healthz"]
+      interval: 5s
+      timeout: 3s
+      retries: 10`,
+	},
+}
+
+type dockerTemplate struct {
+	// Name is the binary and the compose service, the last element of the module path.
+	Name      string
+	Module    string
+	GoVersion string
+	Port      string
+	Services  []composeService
+	Volumes   []string
+}
+
+func dockerData(p *Project) (any, error) {
+	data, err := os.ReadFile(filepath.Join(p.Root, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	mod, err := modfile.ParseLax("go.mod", data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &dockerTemplate{
+		Name:      project.ServiceName(p.Module),
+		Module:    p.Module,
+		GoVersion: "1.26",
+		Port:      "8080",
+	}
+	if mod.Go != nil {
+		// golang images are tagged by minor release, e.g. golang:1.26-alpine
+		parts := strings.SplitN(mod.Go.Version, ".", 3)
+		t.GoVersion = strings.Join(parts[:min(len(parts), 2)], ".")
+	}
+	if env, err := godotenv.Read(filepath.Join(p.Root, ".env")); err == nil && env["APPLICATION_HTTP_PORT"] != "" {
+		t.Port = env["APPLICATION_HTTP_PORT"]
+	}
+
+	manifest, err := project.ReadManifest(p.Root)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range composeServices {
+		if !manifest.HasModule(s.Name) && !requires(mod, s.Requires) {
+			continue
+		}
+		t.Services = append(t.Services, s)
+		if s.Volume != "" {
+			t.Volumes = append(t.Volumes, s.Volume)
+		}
+	}
+	return t, nil
+}
+
+func requires(mod *modfile.File, prefixes []string) bool {
+	for _, r := range mod.Require {
+		for _, prefix := range prefixes {
+			if r.Mod.Path == prefix || strings.HasPrefix(r.Mod.Path, prefix+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// legacyEntrypoint is the start script older templates shipped, it runs a binary that
+// does not exist in generated projects.
+const legacyEntrypoint = "scripts/entrypoint.sh"
+
+func installDocker(p *Project) error {
+	path := filepath.Join(p.Root, legacyEntrypoint)
+	data, err := os.ReadFile(path)
+	if err != nil || !bytes.Contains(data, []byte("/app/roaming-document")) {
+		return nil
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	color.Green("✔ removed %s, the image starts the service directly", legacyEntrypoint)
+	return nil
+}
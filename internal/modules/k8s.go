@@ -0,0 +1,95 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/joho/godotenv"
+
+	"github.com/alwaysgolang/hippo-cli/internal/k8s"
+	"github.com/alwaysgolang/hippo-cli/internal/project"
+)
+
+func init() {
+	register(&Module{
+		Name:        "k8s",
+		Description: "Kubernetes Deployment, Service, ConfigMap and Secret rendered from internal/config",
+		Data:        k8sData,
+		Install:     installK8s,
+		Hints: []string{
+			"Adjust " + k8s.ValuesFile + ", then run: hippo k8s render",
+			"Deploy: hippo k8s render --out - | kubectl apply -f -",
+		},
+	})
+}
+
+type envValue struct {
+	Name string
+	// Value is quoted for YAML.
+	Value string
+}
+
+type k8sTemplate struct {
+	Name    string
+	Image   string
+	Port    int
+	Env     []envValue
+	Unset   []string
+	Secrets []string
+}
+
+// k8sData seeds values.yaml from .env: non-secret values land in env, the rest of the
+// variables internal/config knows are listed commented out.
+func k8sData(p *Project) (any, error) {
+	vars, err := project.ConfigEnv(p.Root)
+	if err != nil {
+		return nil, err
+	}
+	dotenv, err := godotenv.Read(filepath.Join(p.Root, ".env"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	name := project.ServiceName(p.Module)
+	t := &k8sTemplate{Name: name, Image: name + ":latest", Port: 8080}
+	if port, err := strconv.Atoi(dotenv["APPLICATION_HTTP_PORT"]); err == nil {
+		t.Port = port
+	}
+
+	for _, v := range vars {
+		value, set := dotenv[v.Name]
+		switch {
+		case k8s.IsSecret(v.Name):
+			t.Secrets = append(t.Secrets, v.Name)
+		case v.Name == "APPLICATION_HTTP_PORT":
+		case v.Name == "APPLICATION_MODE":
+			t.Env = append(t.Env, envValue{Name: v.Name, Value: strconv.Quote("release")})
+		case set && value != "":
+			t.Env = append(t.Env, envValue{Name: v.Name, Value: strconv.Quote(value)})
+		default:
+			t.Unset = append(t.Unset, v.Name)
+		}
+	}
+	return t, nil
+}
+
+func installK8s(p *Project) error {
+	values, err := k8s.ReadValues(filepath.Join(p.Root, k8s.ValuesFile))
+	if err != nil {
+		return err
+	}
+	manifests, warnings, err := k8s.Render(p.Root, values, false)
+	for _, w := range warnings {
+		color.Yellow("⚠ %s", w)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(p.Root, k8s.ManifestsFile), manifests, 0644); err != nil {
+		return err
+	}
+	color.Green("✔ %s", k8s.ManifestsFile)
+	return nil
+}
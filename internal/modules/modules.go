@@ -0,0 +1,161 @@
+// Package modules adds optional features to a generated project. A module is a template
+// directory under templates/modules plus the edits wiring it into the existing code.
+package modules
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/alwaysgolang/hippo-cli/internal/project"
+	"github.com/alwaysgolang/hippo-cli/internal/wiregen"
+)
+
+type Module struct {
+	Name        string
+	Description string
+	// Data turns the module files into text/templates executed with its result, without
+	// it they are copied with the module path replaced.
+	Data func(p *Project) (any, error)
+	// Install wires the copied files into the project.
+	Install func(p *Project) error
+	// Providers tells that Install changes the wire sets, wire_gen.go is regenerated after it.
+	Providers bool
+	// Hints are printed once the module is installed.
+	Hints []string
+}
+
+// Project is the target of an installation.
+type Project struct {
+	Root   string
+	Module string
+}
+
+var registry = map[string]*Module{}
+
+func register(m *Module) {
+	registry[m.Name] = m
+}
+
+// Names lists the available modules.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func Add(name string) error {
+	m, ok := registry[name]
+	if !ok {
+		return fmt.Errorf("unknown module %q, available: %s", name, strings.Join(Names(), ", "))
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	root, err := project.Root(wd)
+	if err != nil {
+		return err
+	}
+	module, err := project.ModulePath(root)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := project.ReadManifest(root)
+	if err != nil {
+		return err
+	}
+	if manifest.HasModule(name) {
+		color.Yellow("✔ %s is already installed", name)
+		return nil
+	}
+
+	p := &Project{Root: root, Module: module}
+	if m.Data != nil {
+		data, err := m.Data(p)
+		if err != nil {
+			return err
+		}
+		if err := project.RenderTemplate("modules/"+name, root, data); err != nil {
+			return err
+		}
+	} else if err := project.CopyTemplate("modules/"+name, root, module); err != nil {
+		return err
+	}
+	color.Green("✔ copied %s module files", name)
+
+	if m.Install != nil {
+		if err := m.Install(p); err != nil {
+			return err
+		}
+	}
+
+	// the module is recorded last, so hippo add can be run again after a failed step
+	if out, err := goCmd(root, "mod", "tidy"); err != nil {
+		fmt.Println(string(out))
+		return fmt.Errorf("go mod tidy: %w", err)
+	}
+	color.Green("✔ go mod tidy")
+
+	if m.Providers {
+		if err := wiregen.Generate(root); err != nil {
+			color.Yellow("👉 Fix %s, then run: hippo add %s", project.ProvidersFile, name)
+			return err
+		}
+		color.Green("✔ %s", project.WireGenFile)
+	}
+
+	manifest.AddModule(name)
+	if err := project.WriteManifest(root, manifest); err != nil {
+		return err
+	}
+	color.Green("✔ %s", project.ManifestFile)
+
+	for _, hint := range m.Hints {
+		color.Cyan("👉 %s\n", hint)
+	}
+	return nil
+}
+
+// Patch rewrites the Go file rel of the project with edit and formats the result.
+func (p *Project) Patch(rel string, edit func(src []byte) ([]byte, error)) error {
+	path := filepath.Join(p.Root, rel)
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	src, err = edit(src)
+	if err != nil {
+		return fmt.Errorf("%s: %w", rel, err)
+	}
+	if err := project.WriteGoFile(path, src); err != nil {
+		return err
+	}
+	color.Green("✔ %s", rel)
+	return nil
+}
+
+// insertOnce inserts text before the closing bracket of anchor unless marker is already present.
+func insertOnce(src []byte, marker, anchor, text string) ([]byte, error) {
+	if bytes.Contains(src, []byte(marker)) {
+		return src, nil
+	}
+	return project.InsertBeforeClosing(src, anchor, text)
+}
+
+func goCmd(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	return cmd.CombinedOutput()
+}
@@ -0,0 +1,27 @@
+package project
+
+// ArchConfig describes the layers of a project for hippo lint arch. It lives under "arch"
+// in hippo.json.
+type ArchConfig struct {
+	// Layers are ordered from the innermost to the outermost one.
+	Layers []Layer `json:"layers"`
+}
+
+// Layer is a directory of the project, e.g. internal/domain, and everything below it.
+type Layer struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Allow lists the layers this one may import. Without it a layer may import every
+	// layer before it in ArchConfig.Layers.
+	Allow []string `json:"allow,omitempty"`
+}
+
+// DefaultArch is the clean architecture layout of the rest template.
+func DefaultArch() *ArchConfig {
+	return &ArchConfig{Layers: []Layer{
+		{Name: "domain", Path: "internal/domain"},
+		{Name: "usecase", Path: "internal/usecase"},
+		{Name: "adapter", Path: "internal/adapter"},
+		{Name: "infrastructure", Path: "internal/infrastructure"},
+	}}
+}
@@ -0,0 +1,124 @@
+package project
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ConfigDir = "internal/config"
+
+// EnvVar is an environment variable read by the project configuration.
+type EnvVar struct {
+	Name string
+	// Kind is what the value is parsed as: string, int, float, bool, duration or list.
+	Kind string
+	// Required variables are read with a must* helper, which panics when they are unset.
+	Required bool
+}
+
+var envHelpers = map[string]string{
+	"Getenv":         "string",
+	"LookupEnv":      "string",
+	"getString":      "string",
+	"getList":        "list",
+	"mustInt":        "int",
+	"getInt":         "int",
+	"getFloat":       "float",
+	"mustDuration":   "duration",
+	"getDuration":    "duration",
+	"getBool":        "bool",
+	"getBoolDefault": "bool",
+}
+
+// ConfigEnv lists the variables internal/config reads by a literal name, e.g.
+// mustInt("APPLICATION_HTTP_PORT"). Names built at runtime like CLIENT_<NAME>_BASE_URL
+// are not included.
+func ConfigEnv(root string) ([]EnvVar, error) {
+	fset := token.NewFileSet()
+	files, err := filepath.Glob(filepath.Join(root, ConfigDir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	vars := map[string]*EnvVar{}
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			var helper string
+			switch fn := call.Fun.(type) {
+			case *ast.Ident:
+				helper = fn.Name
+			case *ast.SelectorExpr:
+				if pkg, ok := fn.X.(*ast.Ident); ok && pkg.Name == "os" {
+					helper = fn.Sel.Name
+				}
+			}
+			kind, ok := envHelpers[helper]
+			if !ok {
+				return true
+			}
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			name, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				return true
+			}
+
+			v, seen := vars[name]
+			if !seen {
+				v = &EnvVar{Name: name, Kind: kind}
+				vars[name] = v
+			}
+			if kind != "string" {
+				v.Kind = kind
+			}
+			v.Required = v.Required || strings.HasPrefix(helper, "must")
+			return true
+		})
+	}
+
+	result := make([]EnvVar, 0, len(vars))
+	for _, v := range vars {
+		result = append(result, *v)
+	}
+	slices.SortFunc(result, func(a, b EnvVar) int { return strings.Compare(a.Name, b.Name) })
+	return result, nil
+}
+
+// Validate reports whether value parses as v.Kind, as the config helpers would on start.
+func (v EnvVar) Validate(value string) error {
+	var err error
+	switch v.Kind {
+	case "int":
+		_, err = strconv.Atoi(value)
+	case "float":
+		_, err = strconv.ParseFloat(value, 64)
+	case "bool":
+		_, err = strconv.ParseBool(value)
+	case "duration":
+		_, err = time.ParseDuration(value)
+	}
+	if err != nil {
+		return fmt.Errorf("%s=%s is not a valid %s", v.Name, value, v.Kind)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// ManifestFile records what hippo added to a project, so commands can tell which modules are installed.
+const ManifestFile = "hippo.json"
+
+type Manifest struct {
+	Modules []string    `json:"modules"`
+	Arch    *ArchConfig `json:"arch,omitempty"`
+}
+
+// ReadManifest returns an empty manifest when the project has none yet.
+func ReadManifest(root string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(root, ManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return &Manifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *Manifest) HasModule(name string) bool {
+	return slices.Contains(m.Modules, name)
+}
+
+func (m *Manifest) AddModule(name string) {
+	if !m.HasModule(name) {
+		m.Modules = append(m.Modules, name)
+		slices.Sort(m.Modules)
+	}
+}
+
+func WriteManifest(root string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(root, ManifestFile), append(data, '\n'), 0644)
+}
@@ -0,0 +1,195 @@
+package project
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	ProvidersFile = "internal/infrastructure/wire/providers.go"
+	WireGenFile   = "cmd/wire_gen.go"
+)
+
+var ErrNotProject = errors.New("not a hippo project: go.mod not found")
+
+// Root walks up from dir to the directory holding go.mod.
+func Root(dir string) (string, error) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrNotProject
+		}
+		dir = parent
+	}
+}
+
+func ModulePath(root string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+	module := modfile.ModulePath(data)
+	if module == "" {
+		return "", fmt.Errorf("module directive not found in %s", filepath.Join(root, "go.mod"))
+	}
+	return module, nil
+}
+
+var serviceNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// ServiceName names the binary, the container and the Kubernetes objects after the last
+// element of the module path.
+func ServiceName(module string) string {
+	return strings.Trim(serviceNameChars.ReplaceAllString(strings.ToLower(path.Base(module)), "-"), "-")
+}
+
+// AddProvider registers provider (e.g. "billing.NewClient") in the wire set called setName
+// and imports importPath under alias. Running it twice is a no-op.
+func AddProvider(root, setName, alias, importPath, provider string) error {
+	path := filepath.Join(root, ProvidersFile)
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	src, err = AddImport(src, alias, importPath)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Contains(src, []byte(provider+",")) {
+		src, err = InsertBeforeClosing(src, "var "+setName+" = wire.NewSet(", "\n\t"+provider+",\n")
+		if err != nil {
+			return fmt.Errorf("%s: %w", ProvidersFile, err)
+		}
+	}
+
+	return WriteGoFile(path, src)
+}
+
+// AddSet declares a wire set in providers.go, placed before AllProviders and included in it.
+// decl is the full declaration with any helper providers; an existing set is left as is.
+func AddSet(root, setName, decl string, imports map[string]string) error {
+	path := filepath.Join(root, ProvidersFile)
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	for importPath, alias := range imports {
+		if src, err = AddImport(src, alias, importPath); err != nil {
+			return err
+		}
+	}
+
+	if !bytes.Contains(src, []byte("var "+setName+" = wire.NewSet(")) {
+		all := bytes.Index(src, []byte("var AllProviders = wire.NewSet("))
+		if all < 0 {
+			return fmt.Errorf("%s: AllProviders not found", ProvidersFile)
+		}
+		src = append(src[:all:all], append([]byte(decl+"\n\n"), src[all:]...)...)
+
+		src, err = InsertBeforeClosing(src, "var AllProviders = wire.NewSet(", "\t"+setName+",\n")
+		if err != nil {
+			return fmt.Errorf("%s: %w", ProvidersFile, err)
+		}
+	}
+
+	return WriteGoFile(path, src)
+}
+
+// AddImport adds an import spec to the parenthesized import block of src.
+func AddImport(src []byte, alias, importPath string) ([]byte, error) {
+	spec := `"` + importPath + `"`
+	if alias != "" {
+		spec = alias + " " + spec
+	}
+	if bytes.Contains(src, []byte(spec)) {
+		return src, nil
+	}
+	return InsertBeforeClosing(src, "import (", "\t"+spec+"\n")
+}
+
+// InsertBeforeClosing finds anchor, which must end with an opening bracket, and inserts text
+// right before the matching closing bracket. Brackets inside strings are not special-cased,
+// which is fine for the generated files it is used on.
+func InsertBeforeClosing(src []byte, anchor, text string) ([]byte, error) {
+	start := bytes.Index(src, []byte(anchor))
+	if start < 0 {
+		return nil, fmt.Errorf("%q not found", anchor)
+	}
+
+	open := src[start+len(anchor)-1]
+	var closing byte
+	switch open {
+	case '(':
+		closing = ')'
+	case '{':
+		closing = '}'
+	case '[':
+		closing = ']'
+	default:
+		return nil, fmt.Errorf("anchor %q must end with a bracket", anchor)
+	}
+
+	depth := 0
+	for i := start + len(anchor) - 1; i < len(src); i++ {
+		switch src[i] {
+		case open:
+			depth++
+		case closing:
+			depth--
+			if depth == 0 {
+				out := make([]byte, 0, len(src)+len(text))
+				out = append(out, src[:i]...)
+				out = append(out, text...)
+				return append(out, src[i:]...), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unbalanced brackets after %q", anchor)
+}
+
+func WriteGoFile(path string, src []byte) error {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("format %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, formatted, 0644)
+}
+
+// SetEnv appends key to .env unless it is already defined there.
+func SetEnv(root, key, value string) error {
+	path := filepath.Join(root, ".env")
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if name, _, ok := strings.Cut(strings.TrimSpace(line), "="); ok && name == key {
+			return nil
+		}
+	}
+
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	data = append(data, key+"="+value+"\n"...)
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,104 @@
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/alwaysgolang/hippo-cli/templates"
+)
+
+// TemplateModule is the module path the embedded templates are written against.
+const TemplateModule = "gotemplate"
+
+// CopyTemplate copies the embedded directory src into dst, renaming the template module
+// to module. Existing files are left untouched.
+func CopyTemplate(src, dst, module string) error {
+	return fs.WalkDir(templates.FS, src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		if relPath == "." {
+			return nil
+		}
+
+		targetPath := filepath.Join(dst, relPath)
+
+		if d.IsDir() {
+			return os.MkdirAll(targetPath, 0755)
+		}
+
+		// do not overwrite existing files
+		if _, err := os.Stat(targetPath); err == nil {
+			return nil
+		}
+
+		data, err := templates.FS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		content := strings.ReplaceAll(string(data), TemplateModule, module)
+		return os.WriteFile(targetPath, []byte(content), 0644)
+	})
+}
+
+// TemplateFiles returns the files of the embedded directory src keyed by their slash
+// separated path relative to it, with the template module renamed to module.
+func TemplateFiles(src, module string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	err := fs.WalkDir(templates.FS, src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := templates.FS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[strings.TrimPrefix(path, src+"/")] = []byte(strings.ReplaceAll(string(data), TemplateModule, module))
+		return nil
+	})
+	return files, err
+}
+
+// RenderTemplate executes every file of the embedded directory src as a text/template
+// with data and writes the results into dst. Like CopyTemplate it keeps existing files.
+func RenderTemplate(src, dst string, data any) error {
+	return fs.WalkDir(templates.FS, src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		targetPath := filepath.Join(dst, filepath.FromSlash(strings.TrimPrefix(path, src+"/")))
+		if _, err := os.Stat(targetPath); err == nil {
+			return nil
+		}
+
+		text, err := templates.FS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		tmpl, err := template.New(filepath.Base(path)).Option("missingkey=error").Parse(string(text))
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return fmt.Errorf("render %s: %w", path, err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(targetPath, buf.Bytes(), 0644)
+	})
+}
@@ -0,0 +1,168 @@
+// Package wiregen runs google/wire on a project: the wire binary when it is installed,
+// otherwise the same go run the go:generate line of cmd/wire_gen.go uses.
+package wiregen
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/alwaysgolang/hippo-cli/internal/project"
+)
+
+// InjectorPackage holds wire.go and the generated wire_gen.go.
+const InjectorPackage = "./cmd"
+
+var ErrStale = errors.New(project.WireGenFile + " is out of date, run: hippo wire")
+
+// Error is a failed wire run with its output reduced to what points at the cause.
+type Error struct {
+	Lines []string
+}
+
+func (e *Error) Error() string {
+	return "wire failed:\n  " + strings.Join(e.Lines, "\n  ")
+}
+
+// Generate rewrites cmd/wire_gen.go from the provider sets.
+func Generate(root string) error {
+	_, err := run(root, "gen", InjectorPackage)
+	return err
+}
+
+// Check fails with ErrStale when generating would change cmd/wire_gen.go. wire diff prints
+// the difference on stdout, errors on stderr.
+func Check(root string) error {
+	diff, err := run(root, "diff", InjectorPackage)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && strings.TrimSpace(diff) != "" {
+		return ErrStale
+	}
+	return err
+}
+
+// RunGenerate and RunCheck back hippo wire and hippo wire check in the working directory.
+func RunGenerate() error {
+	root, err := root()
+	if err != nil {
+		return err
+	}
+	if err := Generate(root); err != nil {
+		return err
+	}
+	color.Green("✔ %s", project.WireGenFile)
+	return nil
+}
+
+func RunCheck() error {
+	root, err := root()
+	if err != nil {
+		return err
+	}
+	if err := Check(root); err != nil {
+		return err
+	}
+	color.Green("✔ %s is up to date", project.WireGenFile)
+	return nil
+}
+
+// Regenerate is called by generators after they changed providers.go. It reports wire
+// errors without failing, the generated code is in place and the user has to fix the
+// providers anyway.
+func Regenerate(root string) {
+	if err := Generate(root); err != nil {
+		color.Red("✖ %s", err)
+		color.Yellow("👉 Fix %s, then run: hippo wire", project.ProvidersFile)
+		return
+	}
+	color.Green("✔ %s", project.WireGenFile)
+}
+
+func root() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return project.Root(wd)
+}
+
+// run returns the standard output of wire. A failed run is an *Error when wire explained
+// it on stderr, otherwise the exit error itself.
+func run(root string, args ...string) (string, error) {
+	var cmd *exec.Cmd
+	if path, err := exec.LookPath("wire"); err == nil {
+		cmd = exec.Command(path, args...)
+	} else {
+		goArgs := []string{"run"}
+		if args[0] != "diff" {
+			// may add the go.sum entries of wire's own dependencies, the check must not
+			goArgs = append(goArgs, "-mod=mod")
+		}
+		cmd = exec.Command("go", append(append(goArgs, "github.com/google/wire/cmd/wire"), args...)...)
+	}
+	cmd.Dir = root
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if lines := readable(root, stderr.String()); len(lines) > 0 {
+			return stdout.String(), &Error{Lines: lines}
+		}
+		return stdout.String(), err
+	}
+	return stdout.String(), nil
+}
+
+var (
+	// "exit status" is added by go run when wire fails
+	noise = []string{"generate failed", "at least one generate failure", "wrote ", "exit status "}
+	hints = []struct {
+		pattern *regexp.Regexp
+		hint    string
+	}{
+		{regexp.MustCompile(`no provider found for (\S+)`), "add a constructor returning $1 to a set in " + project.ProvidersFile},
+		{regexp.MustCompile(`multiple bindings for (\S+)`), "two providers return $1, remove one or wrap it in a distinct type"},
+		{regexp.MustCompile(`unused provider set "(\w+)"`), "remove $1 from wire.Build in cmd/wire.go or inject one of its types"},
+		{regexp.MustCompile(`unused provider "([\w.]+)"`), "remove $1 from the set or inject what it returns"},
+		{regexp.MustCompile(`cycle for (\S+)`), "$1 depends on itself, break the loop with an interface or a setter"},
+		{regexp.MustCompile(`undefined: (\S+)`), "the code does not compile, fix it and run hippo wire again"},
+	}
+)
+
+// readable drops wire's prefixes and summary lines, shortens paths to the project and
+// adds a hint to the errors it knows.
+func readable(root, output string) []string {
+	var lines []string
+	prefix := filepath.Clean(root) + string(filepath.Separator)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "wire: "))
+		if line == "" || containsAny(line, noise) {
+			continue
+		}
+		line = strings.ReplaceAll(line, prefix, "")
+		lines = append(lines, line)
+		for _, h := range hints {
+			if m := h.pattern.FindStringSubmatchIndex(line); m != nil {
+				lines = append(lines, "  → "+string(h.pattern.ExpandString(nil, h.hint, line, m)))
+				break
+			}
+		}
+	}
+	return lines
+}
+
+func containsAny(s string, parts []string) bool {
+	for _, part := range parts {
+		if strings.Contains(s, part) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,5 +2,5 @@ package templates
 
 import "embed"
 
-//go:embed rest/*
+//go:embed all:rest all:modules
 var FS embed.FS
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"os"
+	"slices"
+	"strings"
+
+	"gotemplate/pkg/auth"
+)
+
+func loadAuth() auth.Config {
+	return auth.Config{
+		JWT: auth.JWTConfig{
+			Issuer:     os.Getenv("AUTH_JWT_ISSUER"),
+			JWKSURL:    os.Getenv("AUTH_JWT_JWKS_URL"),
+			Audience:   os.Getenv("AUTH_JWT_AUDIENCE"),
+			Algorithms: getList("AUTH_JWT_ALGORITHMS"),
+			ScopeClaim: os.Getenv("AUTH_JWT_SCOPE_CLAIM"),
+			RoleClaim:  os.Getenv("AUTH_JWT_ROLE_CLAIM"),
+			CacheTTL:   getDuration("AUTH_JWKS_CACHE_TTL", 0),
+			Leeway:     getDuration("AUTH_JWT_LEEWAY", 0),
+		},
+		APIKeys: loadAPIKeys(),
+	}
+}
+
+// loadAPIKeys collects keys from AUTH_API_KEY_<NAME> with optional comma separated
+// AUTH_API_KEY_<NAME>_SCOPES and AUTH_API_KEY_<NAME>_ROLES.
+func loadAPIKeys() []auth.APIKey {
+	var keys []auth.APIKey
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		name, ok := strings.CutPrefix(key, "AUTH_API_KEY_")
+		if !ok || name == "" || strings.HasSuffix(name, "_SCOPES") || strings.HasSuffix(name, "_ROLES") {
+			continue
+		}
+		keys = append(keys, auth.APIKey{
+			Name:   strings.ToLower(name),
+			Key:    value,
+			Scopes: getList(key + "_SCOPES"),
+			Roles:  getList(key + "_ROLES"),
+		})
+	}
+	slices.SortFunc(keys, func(a, b auth.APIKey) int { return strings.Compare(a.Name, b.Name) })
+	return keys
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+var errInvalidAPIKey = errors.New("invalid api key")
+
+type apiKeyVerifier struct {
+	keys []hashedKey
+}
+
+type hashedKey struct {
+	hash [sha256.Size]byte
+	key  APIKey
+}
+
+// Keys are compared by hash so the comparison time depends neither on the key length
+// nor on how many leading bytes match.
+func newAPIKeyVerifier(keys []APIKey) *apiKeyVerifier {
+	v := &apiKeyVerifier{}
+	for _, key := range keys {
+		v.keys = append(v.keys, hashedKey{hash: sha256.Sum256([]byte(key.Key)), key: key})
+	}
+	return v
+}
+
+func (v *apiKeyVerifier) verify(key string) (*Claims, error) {
+	hash := sha256.Sum256([]byte(key))
+	var match *APIKey
+	for i := range v.keys {
+		if subtle.ConstantTimeCompare(hash[:], v.keys[i].hash[:]) == 1 {
+			match = &v.keys[i].key
+		}
+	}
+	if match == nil {
+		return nil, errInvalidAPIKey
+	}
+	return &Claims{
+		Subject: match.Name,
+		Scopes:  match.Scopes,
+		Roles:   match.Roles,
+		Method:  MethodAPIKey,
+	}, nil
+}
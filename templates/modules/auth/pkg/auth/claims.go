@@ -0,0 +1,68 @@
+// Package auth authenticates requests with JWTs verified against a JWKS or with static API keys.
+//
+// Protect route groups in registerRoutes:
+//
+//	admin := api.Group("/admin", s.Auth.Middleware(), auth.RequireRoles("admin"))
+//	reports := api.Group("/reports", s.Auth.Middleware(), auth.RequireScopes("reports:read"))
+package auth
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+const (
+	MethodJWT    = "jwt"
+	MethodAPIKey = "api_key"
+)
+
+// Claims describe the authenticated caller. Raw holds every JWT claim for the typed Claim accessor.
+type Claims struct {
+	Subject   string         `json:"sub"`
+	Issuer    string         `json:"iss,omitempty"`
+	Audience  []string       `json:"aud,omitempty"`
+	ExpiresAt time.Time      `json:"exp,omitzero"`
+	Scopes    []string       `json:"scopes,omitempty"`
+	Roles     []string       `json:"roles,omitempty"`
+	Method    string         `json:"method"`
+	Raw       map[string]any `json:"-"`
+}
+
+func (c *Claims) HasScope(scope string) bool {
+	return slices.Contains(c.Scopes, scope)
+}
+
+func (c *Claims) HasRole(role string) bool {
+	return slices.Contains(c.Roles, role)
+}
+
+type contextKey struct{}
+
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, contextKey{}, claims)
+}
+
+func FromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(contextKey{}).(*Claims)
+	return claims, ok
+}
+
+// Subject returns the authenticated subject or an empty string.
+func Subject(ctx context.Context) string {
+	if claims, ok := FromContext(ctx); ok {
+		return claims.Subject
+	}
+	return ""
+}
+
+// Claim reads a raw JWT claim as T. JSON numbers are float64, arrays []any and objects map[string]any.
+func Claim[T any](ctx context.Context, name string) (T, bool) {
+	var zero T
+	claims, ok := FromContext(ctx)
+	if !ok {
+		return zero, false
+	}
+	value, ok := claims.Raw[name].(T)
+	return value, ok
+}
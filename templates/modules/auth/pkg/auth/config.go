@@ -0,0 +1,52 @@
+package auth
+
+import "time"
+
+// Config enables JWT authentication when JWT.Issuer or JWT.JWKSURL is set,
+// and API key authentication when APIKeys is not empty.
+type Config struct {
+	JWT     JWTConfig
+	APIKeys []APIKey
+}
+
+type JWTConfig struct {
+	// Issuer is checked against "iss"; without JWKSURL the keys are discovered
+	// through <Issuer>/.well-known/openid-configuration.
+	Issuer     string
+	JWKSURL    string
+	Audience   string
+	Algorithms []string
+	// ScopeClaim holds a space separated string or an array, "scope" by default.
+	ScopeClaim string
+	RoleClaim  string
+	CacheTTL   time.Duration
+	Leeway     time.Duration
+}
+
+// APIKey authenticates callers sending Key in the X-API-Key header as Name.
+type APIKey struct {
+	Name   string
+	Key    string
+	Scopes []string
+	Roles  []string
+}
+
+func (c JWTConfig) enabled() bool {
+	return c.Issuer != "" || c.JWKSURL != ""
+}
+
+func (c JWTConfig) withDefaults() JWTConfig {
+	if len(c.Algorithms) == 0 {
+		c.Algorithms = []string{"RS256", "ES256", "EdDSA"}
+	}
+	if c.ScopeClaim == "" {
+		c.ScopeClaim = "scope"
+	}
+	if c.RoleClaim == "" {
+		c.RoleClaim = "roles"
+	}
+	if c.CacheTTL <= 0 {
+		c.CacheTTL = 10 * time.Minute
+	}
+	return c
+}
@@ -0,0 +1,237 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// minRefreshInterval limits refetches triggered by unknown key ids, so tokens with
+// random kids cannot make us hammer the identity provider.
+const minRefreshInterval = time.Minute
+
+var errUnknownKey = errors.New("unknown signing key")
+
+// JWKS caches the signing keys of an identity provider. Keys are fetched lazily, so an
+// unavailable provider does not prevent the service from starting. Refreshes run in the
+// background while stale keys are still served; only requests without a usable key wait.
+type JWKS struct {
+	url    string
+	issuer string
+	ttl    time.Duration
+	client *http.Client
+
+	mu      sync.Mutex
+	keys    map[string]any
+	fetched time.Time
+	// attempted and err belong to the last refresh, failed ones included
+	attempted time.Time
+	err       error
+	pending   chan struct{}
+}
+
+// NewJWKS reads keys from url, or from the jwks_uri of issuer's OpenID configuration when url is empty.
+func NewJWKS(url, issuer string, ttl time.Duration, client *http.Client) *JWKS {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &JWKS{url: url, issuer: issuer, ttl: ttl, client: client}
+}
+
+func (j *JWKS) Key(ctx context.Context, kid string) (any, error) {
+	j.mu.Lock()
+	key, ok := j.keys[kid]
+	if ok && time.Since(j.fetched) < j.ttl {
+		j.mu.Unlock()
+		return key, nil
+	}
+
+	pending := j.pending
+	if pending == nil && time.Since(j.attempted) >= minRefreshInterval {
+		pending = make(chan struct{})
+		j.pending = pending
+		j.attempted = time.Now()
+		// the fetch outlives the request that triggered it, other requests wait for it too
+		go j.refresh(context.WithoutCancel(ctx), j.url, pending)
+	}
+	if ok || pending == nil {
+		defer j.mu.Unlock()
+		return j.lookup(kid)
+	}
+	j.mu.Unlock()
+
+	select {
+	case <-pending:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.lookup(kid)
+}
+
+// lookup must be called with mu held.
+func (j *JWKS) lookup(kid string) (any, error) {
+	if key, ok := j.keys[kid]; ok {
+		return key, nil
+	}
+	if j.err != nil {
+		return nil, j.err
+	}
+	return nil, errUnknownKey
+}
+
+func (j *JWKS) refresh(ctx context.Context, url string, done chan struct{}) {
+	defer close(done)
+	keys, url, err := j.fetch(ctx, url)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.pending = nil
+	j.err = err
+	if err != nil {
+		return
+	}
+	j.url = url
+	j.keys = keys
+	j.fetched = time.Now()
+}
+
+func (j *JWKS) fetch(ctx context.Context, url string) (map[string]any, string, error) {
+	if url == "" {
+		var err error
+		if url, err = j.discover(ctx); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := j.get(ctx, url, &set); err != nil {
+		return nil, "", fmt.Errorf("fetch jwks: %w", err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	return keys, url, nil
+}
+
+func (j *JWKS) discover(ctx context.Context) (string, error) {
+	var configuration struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	url := strings.TrimSuffix(j.issuer, "/") + "/.well-known/openid-configuration"
+	if err := j.get(ctx, url, &configuration); err != nil {
+		return "", fmt.Errorf("openid discovery: %w", err)
+	}
+	if configuration.JWKSURI == "" {
+		return "", errors.New("openid discovery: jwks_uri is missing")
+	}
+	return configuration.JWKSURI, nil
+}
+
+func (j *JWKS) get(ctx context.Context, url string, result any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeSegment(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeSegment(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		curves := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}
+		curve, ok := curves[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeSegment(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		point := append([]byte{4}, append(leftPad(x, size), leftPad(y, size)...)...)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+func leftPad(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	return append(make([]byte, size-len(b)), b...)
+}
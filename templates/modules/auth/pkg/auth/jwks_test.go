@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJWKSThrottlesFailedRefreshes(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	jwks := NewJWKS(srv.URL, "", time.Hour, srv.Client())
+	for range 3 {
+		if _, err := jwks.Key(context.Background(), "kid"); err == nil {
+			t.Fatal("expected an error while the provider is down")
+		}
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("provider called %d times, want 1 within minRefreshInterval", n)
+	}
+}
+
+func TestJWKSServesStaleKeysWhileRefreshing(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte(`{"keys":[]}`))
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	jwks := NewJWKS(srv.URL, "", time.Minute, srv.Client())
+	stale := []byte("stale")
+	jwks.keys = map[string]any{"kid": stale}
+	jwks.fetched = time.Now().Add(-time.Hour)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 2 {
+			if key, err := jwks.Key(context.Background(), "kid"); err != nil || string(key.([]byte)) != "stale" {
+				t.Errorf("got %v, %v, want the stale key", key, err)
+			}
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Key blocked on the refresh")
+	}
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type jwtVerifier struct {
+	cfg    JWTConfig
+	keys   *JWKS
+	parser *jwt.Parser
+}
+
+func newJWTVerifier(cfg JWTConfig) *jwtVerifier {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(cfg.Algorithms),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+	return &jwtVerifier{
+		cfg:    cfg,
+		keys:   NewJWKS(cfg.JWKSURL, cfg.Issuer, cfg.CacheTTL, nil),
+		parser: jwt.NewParser(options...),
+	}
+}
+
+func (v *jwtVerifier) verify(ctx context.Context, token string) (*Claims, error) {
+	raw := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(token, raw, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	subject, _ := raw.GetSubject()
+	if subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+	issuer, _ := raw.GetIssuer()
+	audience, _ := raw.GetAudience()
+	claims := &Claims{
+		Subject:  subject,
+		Issuer:   issuer,
+		Audience: audience,
+		Scopes:   stringList(raw[v.cfg.ScopeClaim]),
+		Roles:    stringList(raw[v.cfg.RoleClaim]),
+		Method:   MethodJWT,
+		Raw:      raw,
+	}
+	if exp, _ := raw.GetExpirationTime(); exp != nil {
+		claims.ExpiresAt = exp.Time
+	}
+	return claims, nil
+}
+
+// stringList accepts both OAuth style "a b c" strings and JSON arrays.
+func stringList(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	default:
+		return nil
+	}
+}
+
+func bearerToken(header string) (string, error) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", errors.New("malformed authorization header")
+	}
+	return strings.TrimSpace(token), nil
+}
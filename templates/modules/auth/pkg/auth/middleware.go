@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	customErrors "gotemplate/pkg/errors"
+	"gotemplate/pkg/ginplugins"
+	"gotemplate/pkg/logs"
+)
+
+var errNoCredentials = errors.New("no credentials")
+
+// Authenticator verifies bearer JWTs and API keys with the methods enabled in Config.
+type Authenticator struct {
+	jwt     *jwtVerifier
+	apiKeys *apiKeyVerifier
+	realm   string
+}
+
+func NewAuthenticator(cfg *Config) (*Authenticator, error) {
+	a := &Authenticator{realm: "api"}
+	if cfg.JWT.enabled() {
+		jwtCfg := cfg.JWT.withDefaults()
+		if jwtCfg.JWKSURL == "" && !strings.HasPrefix(jwtCfg.Issuer, "https://") && !strings.HasPrefix(jwtCfg.Issuer, "http://") {
+			return nil, fmt.Errorf("auth: issuer %q is not a URL, set a JWKS URL", jwtCfg.Issuer)
+		}
+		a.jwt = newJWTVerifier(jwtCfg)
+	}
+	for _, key := range cfg.APIKeys {
+		if key.Key == "" {
+			return nil, fmt.Errorf("auth: api key %q is empty", key.Name)
+		}
+	}
+	if len(cfg.APIKeys) > 0 {
+		a.apiKeys = newAPIKeyVerifier(cfg.APIKeys)
+	}
+	return a, nil
+}
+
+// Authenticate returns the caller of r. A bearer token takes precedence over an API key.
+func (a *Authenticator) Authenticate(r *http.Request) (*Claims, error) {
+	if header := r.Header.Get("Authorization"); header != "" && a.jwt != nil {
+		token, err := bearerToken(header)
+		if err != nil {
+			return nil, err
+		}
+		return a.jwt.verify(r.Context(), token)
+	}
+	if key := r.Header.Get(APIKeyHeader); key != "" && a.apiKeys != nil {
+		return a.apiKeys.verify(key)
+	}
+	return nil, errNoCredentials
+}
+
+// Middleware rejects unauthenticated requests with 401 and stores the claims in the request context.
+func (a *Authenticator) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := a.Authenticate(c.Request)
+		if err != nil {
+			a.challenge(c, err)
+			return
+		}
+		logs.DebugCtx(c.Request.Context(), "Request authenticated", "subject", claims.Subject, "method", claims.Method)
+		c.Request = c.Request.WithContext(WithClaims(c.Request.Context(), claims))
+		c.Next()
+	}
+}
+
+func (a *Authenticator) challenge(c *gin.Context, err error) {
+	challenge := ""
+	if a.jwt != nil {
+		challenge = fmt.Sprintf(`Bearer realm=%q`, a.realm)
+		if !errors.Is(err, errNoCredentials) {
+			challenge += `, error="invalid_token"`
+		}
+	}
+	if challenge != "" {
+		c.Header("WWW-Authenticate", challenge)
+	}
+	ginplugins.WrapError(customErrors.Wrap(err, customErrors.ErrUnauthorized, "unauthorized", "Authentication is required"), c)
+	c.Abort()
+}
+
+// RequireScopes lets through callers that have every one of scopes.
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := FromContext(c.Request.Context())
+		if !ok {
+			forbid(c, errors.New("request is not authenticated"))
+			return
+		}
+		for _, scope := range scopes {
+			if !claims.HasScope(scope) {
+				forbid(c, fmt.Errorf("%s lacks scope %q", claims.Subject, scope))
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireRoles lets through callers that have at least one of roles.
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := FromContext(c.Request.Context())
+		if !ok {
+			forbid(c, errors.New("request is not authenticated"))
+			return
+		}
+		for _, role := range roles {
+			if claims.HasRole(role) {
+				c.Next()
+				return
+			}
+		}
+		forbid(c, fmt.Errorf("%s has none of roles %v", claims.Subject, roles))
+	}
+}
+
+func forbid(c *gin.Context, err error) {
+	ginplugins.WrapError(customErrors.Wrap(err, customErrors.ErrPermissionDenied, "forbidden", "Not allowed to access this resource"), c)
+	c.Abort()
+}
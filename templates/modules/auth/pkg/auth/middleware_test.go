@@ -0,0 +1,79 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"gotemplate/pkg/auth"
+	"gotemplate/pkg/auth/authtest"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	issuer := authtest.NewIssuer(t)
+	cfg := issuer.Config()
+	cfg.APIKeys = []auth.APIKey{{Name: "reporting", Key: "secret", Scopes: []string{"reports:read"}}}
+	authenticator, err := auth.NewAuthenticator(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	engine := gin.New()
+	api := engine.Group("/", authenticator.Middleware())
+	api.GET("/me", func(c *gin.Context) {
+		tenant, _ := auth.Claim[string](c.Request.Context(), "tenant")
+		c.String(http.StatusOK, auth.Subject(c.Request.Context())+" "+tenant)
+	})
+	api.GET("/admin", auth.RequireRoles("admin"), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	api.GET("/reports", auth.RequireScopes("reports:read"), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	tests := []struct {
+		name   string
+		path   string
+		header func(r *http.Request)
+		status int
+		body   string
+	}{
+		{"no credentials", "/me", func(*http.Request) {}, http.StatusUnauthorized, ""},
+		{"malformed token", "/me", func(r *http.Request) { r.Header.Set("Authorization", "Bearer nope") }, http.StatusUnauthorized, ""},
+		{"jwt", "/me", func(r *http.Request) {
+			issuer.Authorize(t, r, "alice", map[string]any{"tenant": "acme"})
+		}, http.StatusOK, "alice acme"},
+		{"wrong audience", "/me", func(r *http.Request) {
+			issuer.Authorize(t, r, "alice", map[string]any{"aud": "other"})
+		}, http.StatusUnauthorized, ""},
+		{"expired", "/me", func(r *http.Request) {
+			issuer.Authorize(t, r, "alice", map[string]any{"exp": 1})
+		}, http.StatusUnauthorized, ""},
+		{"missing role", "/admin", func(r *http.Request) { issuer.Authorize(t, r, "alice", nil) }, http.StatusForbidden, ""},
+		{"role", "/admin", func(r *http.Request) {
+			issuer.Authorize(t, r, "alice", map[string]any{"roles": []string{"admin"}})
+		}, http.StatusNoContent, ""},
+		{"scope string", "/reports", func(r *http.Request) {
+			issuer.Authorize(t, r, "alice", map[string]any{"scope": "profile reports:read"})
+		}, http.StatusNoContent, ""},
+		{"api key", "/reports", func(r *http.Request) { r.Header.Set(auth.APIKeyHeader, "secret") }, http.StatusNoContent, ""},
+		{"wrong api key", "/reports", func(r *http.Request) { r.Header.Set(auth.APIKeyHeader, "guess") }, http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			tt.header(r)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Fatalf("status %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body %q, want %q", w.Body, tt.body)
+			}
+			if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("WWW-Authenticate header is missing")
+			}
+		})
+	}
+}
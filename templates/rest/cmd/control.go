@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"gotemplate/internal/config"
+	"gotemplate/internal/infrastructure/admin"
+)
+
+// runHealth queries the running instance, a non-nil error makes the process exit with 1
+// so it can be used as a container health check.
+func runHealth(cfg *config.Config) error {
+	health, err := admin.NewClient(cfg.Admin.Socket).Health()
+	if err != nil {
+		for name, result := range health.Checks {
+			if result != admin.StatusOK {
+				err = fmt.Errorf("%w: %s: %s", err, name, result)
+			}
+		}
+		return err
+	}
+	fmt.Println(health.Status)
+	return nil
+}
+
+// runStop asks the running instance to shut down and waits for it to finish.
+func runStop(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("stop", flag.ContinueOnError)
+	timeout := flags.Duration("timeout", 30*time.Second, "how long to wait for a graceful shutdown")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := admin.NewClient(cfg.Admin.Socket).Stop(*timeout); err != nil {
+		return err
+	}
+	fmt.Println("stopped")
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+
+	"gotemplate/internal/config"
+	"gotemplate/internal/infrastructure/admin"
+	"gotemplate/pkg/logs"
+)
+
+const logLevelUsage = `usage:
+  loglevel                                      show levels of the running process
+  loglevel set <level> [--name http] [--for 10m] change the global or a named logger level
+  loglevel reset --name http                    drop the override of a named logger`
+
+// runLogLevel reads or changes log levels of the running process through its admin socket.
+func runLogLevel(cfg *config.Config, args []string) error {
+	client := admin.NewClient(cfg.Admin.Socket)
+
+	if len(args) == 0 {
+		state, err := client.Levels()
+		return printState(state, err)
+	}
+
+	flags := flag.NewFlagSet("loglevel", flag.ContinueOnError)
+	name := flags.String("name", "", "named logger, e.g. http or admin")
+	duration := flags.String("for", "", "revert after this duration, e.g. 10m")
+
+	switch args[0] {
+	case "set":
+		if len(args) < 2 {
+			return errors.New(logLevelUsage)
+		}
+		if err := flags.Parse(args[2:]); err != nil {
+			return err
+		}
+		state, err := client.SetLevel(admin.LevelRequest{Name: *name, Level: args[1], For: *duration})
+		return printState(state, err)
+	case "reset":
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		state, err := client.ResetLevel(*name)
+		return printState(state, err)
+	default:
+		return errors.New(logLevelUsage)
+	}
+}
+
+func printState(state logs.LevelState, err error) error {
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(state)
+}
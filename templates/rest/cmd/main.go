@@ -1,56 +1,88 @@
 package main
 
 import (
+	"context"
 	"errors"
-	"net/http"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"gotemplate/internal/config"
+	"gotemplate/pkg/buildinfo"
+	customErrors "gotemplate/pkg/errors"
 	"gotemplate/pkg/logs"
 	"gotemplate/pkg/plugins"
 )
 
 var quit = make(chan os.Signal, 1)
 
-func runProject(cfg *config.Config) {
+func runProject(cfg *config.Config) error {
 	app, cleanup, err := InitializeApp(cfg)
 	if err != nil {
-		logs.Fatal("Failed to initialize application", "error", err)
+		return fmt.Errorf("initialize application: %w", err)
 	}
 	defer cleanup()
 
-	go func() {
-		logs.Info("Starting HTTP server", "port", cfg.HTTP.Port)
-		if err = app.Server.Run(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			logs.Fatal("server failed to start", "error", err)
-		}
-	}()
-
+	app.Admin.AddCheck("http", app.Server.Check)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+
+	if err := app.Lifecycle.Start(context.Background()); err != nil {
+		return err
+	}
+
+	select {
+	case <-quit:
+	case <-app.Admin.ShutdownRequested():
+	case err = <-app.Lifecycle.Failed():
+		logs.Error("Component failed, shutting down", "error", err)
+	}
+	// a second Ctrl+C kills the process when the graceful shutdown hangs
+	signal.Stop(quit)
+
 	logs.Info("Shutting down gracefully...")
+	return errors.Join(err, app.Lifecycle.Stop().Err())
 }
 
 func main() {
 	cfg := config.Load()
-	logs.Init(cfg.Application.Mode, cfg.Application.LogLevel)
+	if len(os.Args) > 1 {
+		// commands run before logs.Init: they talk to a running instance and must not
+		// open or rotate its log files
+		if err := runCommand(cfg, os.Args[1], os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	logs.Init(cfg.Log.Config)
 	plugins.SetLocation(cfg.Application.TimeZone)
+	plugins.SetLayouts(cfg.Application.DateLayout, cfg.Application.DateTimeLayout)
+	plugins.SetDecimalInputScale(cfg.Application.DecimalInputScale)
+	customErrors.SetStackCapture(cfg.Application.ErrorStackTrace)
 	defer logs.Sync()
-	args := os.Args
 
-	if len(args) < 2 {
-		runProject(cfg)
-		return
+	if err := runProject(cfg); err != nil {
+		logs.Error("Application stopped with error", "error", err)
+		logs.Sync()
+		os.Exit(1)
 	}
+}
 
-	switch args[1] {
+func runCommand(cfg *config.Config, name string, args []string) error {
+	switch name {
 	case "health":
-		logs.Info("Health check success")
+		return runHealth(cfg)
+	case "loglevel":
+		return runLogLevel(cfg, args)
 	case "stop":
-		logs.Info("Stopping Server")
-		quit <- syscall.SIGINT
+		return runStop(cfg, args)
+	case "version":
+		info := buildinfo.Get()
+		fmt.Printf("version %s\ncommit  %s\nbuilt   %s\ngo      %s\n", info.Version, info.Commit, info.Date, info.GoVersion)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q, expected health, stop, loglevel or version", name)
 	}
-
 }
@@ -7,17 +7,25 @@ import (
 	wirePkg "github.com/google/wire"
 
 	"gotemplate/internal/config"
+	"gotemplate/internal/infrastructure/admin"
 	httpServer "gotemplate/internal/infrastructure/http"
 	"gotemplate/internal/infrastructure/wire"
+	"gotemplate/pkg/lifecycle"
+	"gotemplate/pkg/plugins"
 )
 
 type App struct {
-	Server *httpServer.Server
+	Server    *httpServer.Server
+	Admin     *admin.Server
+	Lifecycle *lifecycle.Manager
 }
 
-func NewApp(server *httpServer.Server) *App {
+func NewApp(server *httpServer.Server, adminServer *admin.Server, lc *lifecycle.Manager, clock plugins.Clock) *App {
+	plugins.SetClock(clock)
 	return &App{
-		Server: server,
+		Server:    server,
+		Admin:     adminServer,
+		Lifecycle: lc,
 	}
 }
 
@@ -9,8 +9,12 @@ package main
 import (
 	"gotemplate/internal/adapter/http/controllers/ping"
 	"gotemplate/internal/config"
+	"gotemplate/internal/infrastructure/admin"
 	"gotemplate/internal/infrastructure/http"
 	"gotemplate/internal/infrastructure/wire"
+	"gotemplate/pkg/lifecycle"
+	"gotemplate/pkg/logs"
+	"gotemplate/pkg/plugins"
 )
 
 // Injectors from wire.go:
@@ -18,25 +22,36 @@ import (
 func InitializeApp(cfg *config.Config) (*App, func(), error) {
 	appConfig := wire.ProvideAppConfig(cfg)
 	httpConfig := wire.ProvideHTTPConfig(cfg)
-	controller := ping.NewController()
-	server, cleanup, err := http.NewServer(appConfig, httpConfig, controller)
+	logger := logs.Default()
+	lifecycleConfig := wire.ProvideShutdownConfig(cfg)
+	manager := lifecycle.New(lifecycleConfig, logger)
+	rateLimitStore := wire.ProvideRateLimitStore()
+	clock := wire.ProvideClock()
+	controller := ping.NewController(clock)
+	server, err := http.NewServer(appConfig, httpConfig, logger, manager, rateLimitStore, controller)
 	if err != nil {
 		return nil, nil, err
 	}
-	app := NewApp(server)
+	adminConfig := wire.ProvideAdminConfig(cfg)
+	adminServer := admin.NewServer(adminConfig, logger, manager)
+	app := NewApp(server, adminServer, manager, clock)
 	return app, func() {
-		cleanup()
 	}, nil
 }
 
 // wire.go:
 
 type App struct {
-	Server *http.Server
+	Server    *http.Server
+	Admin     *admin.Server
+	Lifecycle *lifecycle.Manager
 }
 
-func NewApp(server *http.Server) *App {
+func NewApp(server *http.Server, adminServer *admin.Server, lc *lifecycle.Manager, clock plugins.Clock) *App {
+	plugins.SetClock(clock)
 	return &App{
-		Server: server,
+		Server:    server,
+		Admin:     adminServer,
+		Lifecycle: lc,
 	}
 }
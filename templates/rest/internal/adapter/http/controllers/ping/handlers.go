@@ -2,19 +2,29 @@ package ping
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"gotemplate/pkg/plugins"
 )
 
 type Controller struct {
+	clock plugins.Clock
+}
+
+type Response struct {
+	Message string    `json:"message" binding:"required" example:"pong"`
+	Time    time.Time `json:"time" binding:"required"`
 }
 
 func (c *Controller) Ping(ctx *gin.Context) {
-	ctx.JSON(http.StatusOK, gin.H{
-		"message": "pong",
+	ctx.JSON(http.StatusOK, Response{
+		Message: "pong",
+		Time:    c.clock.Now().In(plugins.LocationFromContext(ctx.Request.Context())),
 	})
 }
 
-func NewController() *Controller {
-	return &Controller{}
+func NewController(clock plugins.Clock) *Controller {
+	return &Controller{clock: clock}
 }
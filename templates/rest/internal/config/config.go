@@ -1,34 +1,191 @@
 package config
 
 import (
+	"errors"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"4d63.com/tz"
 	"github.com/joho/godotenv"
+
+	"gotemplate/pkg/lifecycle"
+	"gotemplate/pkg/logs"
+	"gotemplate/pkg/middleware"
 )
 
 func Load() *Config {
-	if err := godotenv.Load(); err != nil {
+	// containers get their environment from the orchestrator and ship no .env
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
 		panic("Error loading .env file")
 	}
 
 	cfg := Config{
 		HTTP: HTTPConfig{
-			Port: mustInt("APPLICATION_HTTP_PORT"),
+			Port:              mustInt("APPLICATION_HTTP_PORT"),
+			Host:              os.Getenv("APPLICATION_HTTP_HOST"),
+			Socket:            os.Getenv("APPLICATION_HTTP_SOCKET"),
+			ReadHeaderTimeout: getDuration("APPLICATION_HTTP_READ_HEADER_TIMEOUT", time.Second),
+			ReadTimeout:       getDuration("APPLICATION_HTTP_READ_TIMEOUT", 30*time.Second),
+			WriteTimeout:      getDuration("APPLICATION_HTTP_WRITE_TIMEOUT", 30*time.Second),
+			IdleTimeout:       getDuration("APPLICATION_HTTP_IDLE_TIMEOUT", 2*time.Minute),
+			MaxHeaderBytes:    getInt("APPLICATION_HTTP_MAX_HEADER_BYTES", 1<<20),
+			MaxBodyBytes:      int64(getInt("APPLICATION_HTTP_MAX_BODY_BYTES", 10<<20)),
+			TLS:               loadTLS(),
+			HTTP2: HTTP2Config{
+				Enabled:              getBoolDefault("APPLICATION_HTTP2", true),
+				Cleartext:            getBool("APPLICATION_HTTP2_CLEARTEXT"),
+				MaxConcurrentStreams: getInt("APPLICATION_HTTP2_MAX_CONCURRENT_STREAMS", 0),
+				MaxReadFrameSize:     getInt("APPLICATION_HTTP2_MAX_READ_FRAME_SIZE", 0),
+			},
+			CORS: middleware.CORSConfig{
+				AllowOrigins:     getList("CORS_ALLOW_ORIGINS"),
+				AllowMethods:     getList("CORS_ALLOW_METHODS"),
+				AllowHeaders:     getList("CORS_ALLOW_HEADERS"),
+				ExposeHeaders:    getList("CORS_EXPOSE_HEADERS"),
+				AllowCredentials: getBool("CORS_ALLOW_CREDENTIALS"),
+				MaxAge:           getDuration("CORS_MAX_AGE", 10*time.Minute),
+			},
+			SecurityHeaders: loadSecurityHeaders(),
+			RateLimit: RateLimitConfig{
+				Limit: middleware.Limit{
+					Rate:  getFloat("RATE_LIMIT_RPS", 0),
+					Burst: getInt("RATE_LIMIT_BURST", 20),
+				},
+				Key: os.Getenv("RATE_LIMIT_KEY"),
+			},
+		},
+
+		Admin: AdminConfig{
+			Socket: getAdminSocket(),
+		},
+
+		Shutdown: lifecycle.Config{
+			PreStopDelay: getDuration("SHUTDOWN_PRE_STOP_DELAY", 0),
+			Timeout:      getDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
 		},
 
 		Application: AppConfig{
-			Mode:     os.Getenv("APPLICATION_MODE"),
-			TimeZone: getTimeZone(os.Getenv("TIMEZONE")),
-			LogLevel: os.Getenv("LOG_LEVEL"),
+			Mode:              os.Getenv("APPLICATION_MODE"),
+			TimeZone:          getTimeZone(os.Getenv("TIMEZONE")),
+			TimeZoneHeader:    getString("TIMEZONE_HEADER", middleware.TimezoneHeader),
+			DateLayout:        getString("DATE_LAYOUT", time.DateOnly),
+			DateTimeLayout:    getString("DATETIME_LAYOUT", time.RFC3339),
+			DecimalInputScale: int32(getInt("DECIMAL_INPUT_SCALE", 9)),
+			LogLevel:          os.Getenv("LOG_LEVEL"),
+			ErrorTypeBaseURI:  os.Getenv("ERROR_TYPE_BASE_URI"),
+			ErrorStackTrace:   getBool("ERROR_STACK_TRACE"),
 		},
+
+		Log: loadLog(),
+
+		Clients: loadClients(),
 	}
 
 	return &cfg
 }
 
+// loadTLS requires the certificate and the key together, one of them alone is a typo
+// that would otherwise serve plain HTTP or fail after the start.
+func loadTLS() TLSConfig {
+	cfg := TLSConfig{
+		CertFile:           os.Getenv("APPLICATION_HTTP_TLS_CERT_FILE"),
+		KeyFile:            os.Getenv("APPLICATION_HTTP_TLS_KEY_FILE"),
+		ClientCAFile:       os.Getenv("APPLICATION_HTTP_TLS_CLIENT_CA_FILE"),
+		ClientAuthOptional: getBool("APPLICATION_HTTP_TLS_CLIENT_AUTH_OPTIONAL"),
+	}
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		panic("APPLICATION_HTTP_TLS_CERT_FILE and APPLICATION_HTTP_TLS_KEY_FILE must be set together")
+	}
+	if cfg.ClientCAFile != "" && !cfg.Enabled() {
+		panic("APPLICATION_HTTP_TLS_CLIENT_CA_FILE needs APPLICATION_HTTP_TLS_CERT_FILE and APPLICATION_HTTP_TLS_KEY_FILE")
+	}
+	return cfg
+}
+
+// loadSecurityHeaders starts from middleware.DefaultSecurityHeaders, SECURITY_HEADERS=false turns them off.
+func loadSecurityHeaders() middleware.SecurityHeadersConfig {
+	if !getBoolDefault("SECURITY_HEADERS", true) {
+		return middleware.SecurityHeadersConfig{}
+	}
+
+	headers := middleware.DefaultSecurityHeaders
+	headers.HSTSMaxAge = getDuration("SECURITY_HSTS_MAX_AGE", headers.HSTSMaxAge)
+	headers.HSTSPreload = getBool("SECURITY_HSTS_PRELOAD")
+	if csp, ok := os.LookupEnv("SECURITY_CSP"); ok {
+		headers.ContentSecurityPolicy = csp
+	}
+	return headers
+}
+
+// getAdminSocket defaults to a path derived from the HTTP port,
+// which is already unique for every service on the host.
+func getAdminSocket() string {
+	if socket := os.Getenv("ADMIN_SOCKET"); socket != "" {
+		return socket
+	}
+	return filepath.Join(os.TempDir(), "admin-"+strconv.Itoa(mustInt("APPLICATION_HTTP_PORT"))+".sock")
+}
+
+func loadLog() LogConfig {
+	mode := os.Getenv("APPLICATION_MODE")
+	// production keeps zap's default sampling unless configured otherwise
+	sampling := 100
+	if mode == "debug" {
+		sampling = 0
+	}
+
+	cfg := logs.Config{
+		Mode:     mode,
+		Level:    os.Getenv("LOG_LEVEL"),
+		Encoding: os.Getenv("LOG_ENCODING"),
+		Sampling: logs.SamplingConfig{
+			Tick:       getDuration("LOG_SAMPLING_TICK", time.Second),
+			First:      getInt("LOG_SAMPLING_FIRST", sampling),
+			Thereafter: getInt("LOG_SAMPLING_THEREAFTER", sampling),
+		},
+		Async: logs.AsyncConfig{
+			Enabled:       getBool("LOG_ASYNC"),
+			BufferSize:    getInt("LOG_ASYNC_BUFFER_SIZE", 256*1024),
+			FlushInterval: getDuration("LOG_ASYNC_FLUSH_INTERVAL", 30*time.Second),
+		},
+		Redact: logs.RedactConfig{
+			Keys:      getList("LOG_REDACT_KEYS"),
+			Headers:   getList("LOG_REDACT_HEADERS"),
+			JSONPaths: getList("LOG_REDACT_JSON_PATHS"),
+			// regular expressions may contain commas, so they are separated by spaces
+			Patterns: strings.Fields(os.Getenv("LOG_REDACT_PATTERNS")),
+		},
+	}
+
+	// LOG_SINKS=console,file with LOG_SINK_<NAME>_OUTPUT, _LEVEL, _ENCODING and for files
+	// _MAX_SIZE_MB, _ROTATE_INTERVAL, _MAX_AGE, _MAX_BACKUPS
+	for _, name := range getList("LOG_SINKS") {
+		prefix := "LOG_SINK_" + strings.ToUpper(name) + "_"
+		output := os.Getenv(prefix + "OUTPUT")
+		if output == "" {
+			output = name
+		}
+		cfg.Sinks = append(cfg.Sinks, logs.SinkConfig{
+			Name:     name,
+			Output:   output,
+			Level:    os.Getenv(prefix + "LEVEL"),
+			Encoding: os.Getenv(prefix + "ENCODING"),
+			Rotation: logs.RotationConfig{
+				MaxSizeMB:  getInt(prefix+"MAX_SIZE_MB", 0),
+				Interval:   getDuration(prefix+"ROTATE_INTERVAL", 0),
+				MaxAge:     getDuration(prefix+"MAX_AGE", 0),
+				MaxBackups: getInt(prefix+"MAX_BACKUPS", 0),
+			},
+		})
+	}
+
+	return LogConfig{Config: cfg}
+}
+
 func mustInt(key string) int {
 	val := os.Getenv(key)
 	n, err := strconv.Atoi(val)
@@ -38,6 +195,66 @@ func mustInt(key string) int {
 	return n
 }
 
+func getInt(key string, fallback int) int {
+	if os.Getenv(key) == "" {
+		return fallback
+	}
+	return mustInt(key)
+}
+
+func getFloat(key string, fallback float64) float64 {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		panic("Invalid number for " + key)
+	}
+	return f
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	if os.Getenv(key) == "" {
+		return fallback
+	}
+	return mustDuration(key)
+}
+
+// getString tells an empty value apart from an unset one, so a default can be switched off.
+func getString(key, fallback string) string {
+	if val, ok := os.LookupEnv(key); ok {
+		return val
+	}
+	return fallback
+}
+
+func getBool(key string) bool {
+	return getBoolDefault(key, false)
+}
+
+func getBoolDefault(key string, fallback bool) bool {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		panic("Invalid boolean for " + key)
+	}
+	return b
+}
+
+func getList(key string) []string {
+	var result []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 func getTimeZone(location string) *time.Location {
 	timezone, err := tz.LoadLocation(location)
 	if err != nil {
@@ -45,3 +262,35 @@ func getTimeZone(location string) *time.Location {
 	}
 	return timezone
 }
+
+// loadClients collects upstream settings from CLIENT_<NAME>_BASE_URL and CLIENT_<NAME>_TIMEOUT.
+func loadClients() map[string]ClientConfig {
+	clients := make(map[string]ClientConfig)
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		name, ok := strings.CutPrefix(key, "CLIENT_")
+		if !ok {
+			continue
+		}
+		name, ok = strings.CutSuffix(name, "_BASE_URL")
+		if !ok || name == "" {
+			continue
+		}
+
+		client := ClientConfig{BaseURL: value}
+		if timeout := os.Getenv("CLIENT_" + name + "_TIMEOUT"); timeout != "" {
+			client.Timeout = mustDuration("CLIENT_" + name + "_TIMEOUT")
+		}
+		clients[strings.ToLower(name)] = client
+	}
+	return clients
+}
+
+func mustDuration(key string) time.Duration {
+	val := os.Getenv(key)
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		panic("Invalid duration for " + key)
+	}
+	return d
+}
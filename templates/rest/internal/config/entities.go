@@ -1,20 +1,99 @@
 package config
 
-import "time"
+import (
+	"time"
+
+	"gotemplate/pkg/lifecycle"
+	"gotemplate/pkg/logs"
+	"gotemplate/pkg/middleware"
+)
 
 type Config struct {
 	HTTP        HTTPConfig
+	Admin       AdminConfig
+	Shutdown    lifecycle.Config
 	Application AppConfig
+	Log         LogConfig
+	Clients     map[string]ClientConfig
 }
 
 type HTTPConfig struct {
 	Port int
 	Mode string
+	// Host restricts the listen address, all interfaces when empty.
+	Host string
+	// Socket makes the server listen on a Unix socket instead of Host and Port.
+	Socket string
+
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	// MaxBodyBytes limits request bodies, zero disables the limit.
+	MaxBodyBytes int64
+
+	TLS   TLSConfig
+	HTTP2 HTTP2Config
+
+	CORS            middleware.CORSConfig
+	SecurityHeaders middleware.SecurityHeadersConfig
+	RateLimit       RateLimitConfig
+}
+
+// RateLimitConfig is disabled when Limit.Rate is zero. Key is "ip", "route" or "header:<Name>".
+type RateLimitConfig struct {
+	Limit middleware.Limit
+	Key   string
+}
+
+// TLSConfig enables HTTPS when CertFile and KeyFile are set. ClientCAFile turns on mTLS:
+// client certificates are required unless ClientAuthOptional is set.
+type TLSConfig struct {
+	CertFile           string
+	KeyFile            string
+	ClientCAFile       string
+	ClientAuthOptional bool
+}
+
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+type HTTP2Config struct {
+	Enabled bool
+	// Cleartext serves HTTP/2 without TLS (h2c), e.g. behind a proxy that terminates TLS.
+	Cleartext            bool
+	MaxConcurrentStreams int
+	MaxReadFrameSize     int
+}
+
+// AdminConfig configures the control socket used by the health, stop and loglevel subcommands.
+type AdminConfig struct {
+	Socket string
 }
 
 type AppConfig struct {
-	Mode              string
-	TimeZone          *time.Location
+	Mode     string
+	TimeZone *time.Location
+	// TimeZoneHeader lets callers override TimeZone per request, empty disables it.
+	TimeZoneHeader string
+	// DateLayout and DateTimeLayout are the output formats of plugins.DateFormat and DateTimeFormat.
+	DateLayout     string
+	DateTimeLayout string
+	// DecimalInputScale is the number of fractional digits plugins.Decimal accepts from clients.
+	DecimalInputScale int32
 	LogLevel          string
 	ConsumeOnCallback bool
+	ErrorTypeBaseURI  string
+	ErrorStackTrace   bool
+}
+
+type LogConfig struct {
+	logs.Config
+}
+
+type ClientConfig struct {
+	BaseURL string
+	Timeout time.Duration
 }
@@ -0,0 +1,124 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"gotemplate/pkg/logs"
+)
+
+// Client talks to the admin Server of a running process, it backs the cmd subcommands.
+type Client struct {
+	http *http.Client
+}
+
+func NewClient(socket string) *Client {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		},
+	}
+	return &Client{http: &http.Client{Transport: transport, Timeout: 5 * time.Second}}
+}
+
+// Health returns the reported state even when the process is unhealthy,
+// err is set for anything but a healthy instance.
+func (c *Client) Health() (Health, error) {
+	var health Health
+	err := c.do(http.MethodGet, "/health", nil, &health)
+	if errors.As(err, new(*statusError)) && health.Status != "" {
+		return health, fmt.Errorf("instance is %s", health.Status)
+	}
+	return health, err
+}
+
+// Stop asks the running instance to shut down and waits until its socket closes,
+// which happens after every other component has stopped.
+func (c *Client) Stop(timeout time.Duration) error {
+	if err := c.do(http.MethodPost, "/shutdown", nil, nil); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if _, err := c.Health(); errors.As(err, new(*net.OpError)) {
+			return nil
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	return fmt.Errorf("instance did not stop within %s", timeout)
+}
+
+func (c *Client) Levels() (logs.LevelState, error) {
+	var state logs.LevelState
+	err := c.do(http.MethodGet, "/log-level", nil, &state)
+	return state, err
+}
+
+func (c *Client) SetLevel(req LevelRequest) (logs.LevelState, error) {
+	var state logs.LevelState
+	err := c.do(http.MethodPut, "/log-level", req, &state)
+	return state, err
+}
+
+func (c *Client) ResetLevel(name string) (logs.LevelState, error) {
+	var state logs.LevelState
+	err := c.do(http.MethodDelete, "/log-level?name="+url.QueryEscape(name), nil, &state)
+	return state, err
+}
+
+func (c *Client) do(method, path string, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	// the host is ignored by the unix dialer
+	req, err := http.NewRequest(method, "http://admin"+path, reader)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("admin socket: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if result != nil {
+		_ = json.Unmarshal(data, result)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		var problem struct {
+			Error string `json:"error"`
+		}
+		_ = json.Unmarshal(data, &problem)
+		return &statusError{status: resp.Status, message: problem.Error}
+	}
+	return nil
+}
+
+type statusError struct {
+	status  string
+	message string
+}
+
+func (e *statusError) Error() string {
+	return "admin: " + e.status + ": " + e.message
+}
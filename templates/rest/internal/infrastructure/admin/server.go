@@ -0,0 +1,234 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"gotemplate/internal/config"
+	"gotemplate/pkg/lifecycle"
+	"gotemplate/pkg/logs"
+)
+
+// Server is a control API on a Unix socket used by the health, stop and loglevel subcommands.
+// Access is granted by file permissions: the socket is created with 0600, so only the user
+// running the service can reach it.
+type Server struct {
+	cfg        *config.AdminConfig
+	httpServer *http.Server
+	log        *logs.Logger
+
+	lifecycle *lifecycle.Manager
+	mu        sync.Mutex
+	checks    map[string]Check
+	requested bool
+	listening bool
+	shutdown  chan struct{}
+}
+
+// Check reports whether a component of the running process is healthy.
+type Check func(ctx context.Context) error
+
+type Health struct {
+	Status string            `json:"status"`
+	PID    int               `json:"pid"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+const (
+	StatusOK       = "ok"
+	StatusFailing  = "failing"
+	StatusStopping = "stopping"
+)
+
+// LevelRequest changes a level, Name selects a logger and For makes the change temporary.
+type LevelRequest struct {
+	Name  string `json:"name,omitempty"`
+	Level string `json:"level"`
+	For   string `json:"for,omitempty"`
+}
+
+// NewServer registers the socket to start first and stop last: the stop subcommand
+// waits for it to disappear, which then means every other component has stopped.
+func NewServer(cfg *config.AdminConfig, logger *logs.Logger, lc *lifecycle.Manager) *Server {
+	server := &Server{
+		cfg:       cfg,
+		log:       logger.Named("admin"),
+		lifecycle: lc,
+		checks:    make(map[string]Check),
+		shutdown:  make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", server.health)
+	mux.HandleFunc("POST /shutdown", server.requestShutdown)
+	mux.HandleFunc("GET /log-level", server.getLevel)
+	mux.HandleFunc("PUT /log-level", server.setLevel)
+	mux.HandleFunc("DELETE /log-level", server.resetLevel)
+	server.httpServer = &http.Server{Handler: mux, ReadHeaderTimeout: time.Second}
+
+	lc.Append(lifecycle.Hook{
+		Name:     "admin",
+		Priority: lifecycle.PriorityControl,
+		OnStart:  server.start,
+		OnStop:   server.stop,
+	})
+
+	return server
+}
+
+// AddCheck registers a component checked by the health subcommand.
+func (s *Server) AddCheck(name string, check Check) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks[name] = check
+}
+
+// ShutdownRequested is closed when the stop subcommand asks the process to exit.
+func (s *Server) ShutdownRequested() <-chan struct{} {
+	return s.shutdown
+}
+
+// start does not fail the application: it runs without the socket, only losing the subcommands.
+func (s *Server) start(context.Context) error {
+	listener, err := s.listen()
+	if err != nil {
+		s.log.Warn("Admin socket is not available", "socket", s.cfg.Socket, "error", err)
+		return nil
+	}
+	s.listening = true
+
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.log.Warn("Admin socket stopped", "socket", s.cfg.Socket, "error", err)
+		}
+	}()
+	return nil
+}
+
+func (s *Server) stop(ctx context.Context) error {
+	if !s.listening {
+		return nil
+	}
+	defer func() { _ = os.Remove(s.cfg.Socket) }()
+	return s.httpServer.Shutdown(ctx)
+}
+
+func (s *Server) listen() (net.Listener, error) {
+	// a socket left by a killed process would make Listen fail
+	if conn, err := net.Dial("unix", s.cfg.Socket); err == nil {
+		_ = conn.Close()
+		return nil, errors.New("admin socket " + s.cfg.Socket + " is in use by another process")
+	}
+	_ = os.Remove(s.cfg.Socket)
+
+	listener, err := net.Listen("unix", s.cfg.Socket)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(s.cfg.Socket, 0600); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+func (s *Server) health(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	checks := make(map[string]Check, len(s.checks))
+	for name, check := range s.checks {
+		checks[name] = check
+	}
+	s.mu.Unlock()
+
+	result := Health{Status: StatusOK, PID: os.Getpid(), Checks: make(map[string]string, len(checks))}
+	if s.lifecycle.Stopping() {
+		result.Status = StatusStopping
+		writeJSON(w, http.StatusServiceUnavailable, result)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+	for name, check := range checks {
+		if err := check(ctx); err != nil {
+			result.Status = StatusFailing
+			result.Checks[name] = err.Error()
+			continue
+		}
+		result.Checks[name] = StatusOK
+	}
+
+	status := http.StatusOK
+	if result.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, result)
+}
+
+func (s *Server) requestShutdown(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	if !s.requested {
+		s.requested = true
+		close(s.shutdown)
+		s.log.Info("Shutdown requested through admin socket")
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusAccepted, Health{Status: StatusStopping, PID: os.Getpid()})
+}
+
+func (s *Server) getLevel(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, logs.Levels())
+}
+
+func (s *Server) setLevel(w http.ResponseWriter, r *http.Request) {
+	var req LevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var ttl time.Duration
+	if req.For != "" {
+		var err error
+		if ttl, err = time.ParseDuration(req.For); err != nil || ttl <= 0 {
+			writeError(w, http.StatusBadRequest, errors.New("invalid duration "+req.For))
+			return
+		}
+	}
+	if err := logs.SetLevel(req.Name, req.Level, ttl); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.log.Warn("Log level changed", "name", req.Name, "level", req.Level, "for", req.For)
+	writeJSON(w, http.StatusOK, logs.Levels())
+}
+
+func (s *Server) resetLevel(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, errors.New("name is required, set the global level instead"))
+		return
+	}
+
+	logs.ResetLevel(name)
+	s.log.Warn("Log level override removed", "name", name)
+	writeJSON(w, http.StatusOK, logs.Levels())
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
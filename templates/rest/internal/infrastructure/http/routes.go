@@ -1,8 +1,37 @@
 package http
 
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	pingController "gotemplate/internal/adapter/http/controllers/ping"
+	"gotemplate/pkg/openapi"
+)
+
 func (s *Server) registerRoutes() {
-	api := s.Engine.Group("/api")
+	api := openapi.NewRouter(s.Engine.Group("/api"), s.Docs)
 	{
-		api.GET("/ping", s.PingController.Ping)
+		api.GET("/ping", openapi.Route{
+			OperationID: "ping",
+			Summary:     "Liveness check",
+			Tags:        []string{"system"},
+			Response:    pingController.Response{},
+		}, s.PingController.Ping)
+	}
+
+	s.Engine.GET("/ready", s.ready)
+	s.Engine.GET("/openapi.json", s.Docs.Handler())
+	if s.appCfg.Mode == gin.DebugMode {
+		s.Engine.GET("/swagger/*file", openapi.UIHandler("/openapi.json"))
+	}
+}
+
+// ready is the readiness probe: it fails as soon as shutdown begins, while requests are still served.
+func (s *Server) ready(c *gin.Context) {
+	if !s.lifecycle.Ready() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "not ready"})
+		return
 	}
+	c.JSON(http.StatusOK, gin.H{"status": "ready"})
 }
@@ -2,13 +2,26 @@ package http
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
 	"strconv"
 	"time"
 
 	pingController "gotemplate/internal/adapter/http/controllers/ping"
 	"gotemplate/internal/config"
+	"gotemplate/pkg/buildinfo"
+	customErrors "gotemplate/pkg/errors"
+	"gotemplate/pkg/ginplugins"
+	"gotemplate/pkg/lifecycle"
 	"gotemplate/pkg/logs"
+	"gotemplate/pkg/middleware"
+	"gotemplate/pkg/openapi"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -19,6 +32,9 @@ type Server struct {
 	httpServer     *http.Server
 	appCfg         *config.AppConfig
 	httpCfg        *config.HTTPConfig
+	Docs           *openapi.Document
+	log            *logs.Logger
+	lifecycle      *lifecycle.Manager
 	PingController *pingController.Controller
 }
 
@@ -27,6 +43,10 @@ const (
 	requestIDKey    = "request_id"
 )
 
+// unlimitedRoutes are probed by the kubelet or fetched by tooling, a 429 there would
+// restart pods or break the docs.
+var unlimitedRoutes = []string{"/api/ping", "/ready", "/openapi.json", "/swagger/*file"}
+
 func requestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		requestID := c.GetHeader(RequestIDHeader)
@@ -44,7 +64,7 @@ func requestIDMiddleware() gin.HandlerFunc {
 	}
 }
 
-func zapLogger(cfg *config.AppConfig) gin.HandlerFunc {
+func zapLogger(log *logs.Logger, cfg *config.AppConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
@@ -56,17 +76,17 @@ func zapLogger(cfg *config.AppConfig) gin.HandlerFunc {
 		latency := end.Sub(start)
 
 		if cfg.Mode == gin.DebugMode {
-			logs.InfoCtx(c, "HTTP Request",
+			log.InfoCtx(c, "HTTP Request",
 				"status", c.Writer.Status(),
 				"method", c.Request.Method,
 				"path", path,
-				"query", query,
+				"query", logs.RedactQuery(query),
 				"ip", c.ClientIP(),
 				"user-agent", c.Request.UserAgent(),
 				"latency", latency,
 			)
 		} else {
-			logs.InfoCtx(c, "HTTP Request",
+			log.InfoCtx(c, "HTTP Request",
 				"status", c.Writer.Status(),
 				"method", c.Request.Method,
 				"path", path,
@@ -76,15 +96,32 @@ func zapLogger(cfg *config.AppConfig) gin.HandlerFunc {
 	}
 }
 
-func zapRecovery() gin.HandlerFunc {
+// maxBodyBytes rejects declared oversized bodies upfront and cuts the rest while reading,
+// binding helpers then report ErrTooLarge.
+func maxBodyBytes(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > limit {
+			ginplugins.WrapError(customErrors.WrapTooLargeError(
+				fmt.Errorf("request body of %d bytes exceeds the limit of %d", c.Request.ContentLength, limit),
+			), c)
+			c.Abort()
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+func zapRecovery(log *logs.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				logs.ErrorCtx(c.Request.Context(), "Panic recovered",
+				log.ErrorCtx(c.Request.Context(), "Panic recovered",
 					"error", err,
 					"path", c.Request.URL.Path,
 				)
-				c.AbortWithStatus(500)
+				ginplugins.WrapError(customErrors.WrapSystemError(fmt.Errorf("panic: %v", err)), c)
+				c.Abort()
 			}
 		}()
 		c.Next()
@@ -94,40 +131,207 @@ func zapRecovery() gin.HandlerFunc {
 func NewServer(
 	appCfg *config.AppConfig,
 	httpCfg *config.HTTPConfig,
+	logger *logs.Logger,
+	lc *lifecycle.Manager,
+	rateLimitStore middleware.RateLimitStore,
 	pingCtrl *pingController.Controller,
-) (*Server, func(), error) {
+) (*Server, error) {
 	gin.SetMode(appCfg.Mode)
+	ginplugins.InitValidation()
+	ginplugins.SetProblemTypeBaseURI(appCfg.ErrorTypeBaseURI)
 	engine := gin.New()
+	log := logger.Named("http")
 
 	engine.Use(requestIDMiddleware())
-	engine.Use(zapRecovery())
-	engine.Use(zapLogger(appCfg))
+	engine.Use(zapRecovery(log))
+	engine.Use(zapLogger(log, appCfg))
+	engine.Use(middleware.SecurityHeaders(httpCfg.SecurityHeaders))
+	if len(httpCfg.CORS.AllowOrigins) > 0 {
+		if err := httpCfg.CORS.Validate(); err != nil {
+			return nil, err
+		}
+		engine.Use(middleware.CORS(httpCfg.CORS))
+	}
+	if httpCfg.MaxBodyBytes > 0 {
+		engine.Use(maxBodyBytes(httpCfg.MaxBodyBytes))
+	}
+	if httpCfg.RateLimit.Limit.Rate > 0 {
+		key, err := middleware.ParseKey(httpCfg.RateLimit.Key)
+		if err != nil {
+			return nil, err
+		}
+		engine.Use(middleware.RateLimit(rateLimitStore, httpCfg.RateLimit.Limit, middleware.SkipRoutes(key, unlimitedRoutes...)))
+	}
+	if appCfg.TimeZoneHeader != "" {
+		engine.Use(middleware.Timezone(appCfg.TimeZoneHeader, nil))
+	}
 
 	server := &Server{
 		appCfg:         appCfg,
 		httpCfg:        httpCfg,
 		Engine:         engine,
+		Docs:           openapi.New("gotemplate", buildinfo.Version),
+		log:            log,
+		lifecycle:      lc,
 		PingController: pingCtrl,
 	}
 
-	cleanupServer := func() {
-		logs.Info("Shutting down HTTP server...")
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-		if server.httpServer != nil {
-			_ = server.httpServer.Shutdown(ctx)
+	lc.Append(lifecycle.Hook{
+		Name:     "http",
+		Priority: lifecycle.PriorityServer,
+		OnStart:  server.start,
+		OnStop:   server.stop,
+	})
+
+	return server, nil
+}
+
+// Check sends GET /api/ping through the configured socket or port and scheme, so the
+// health subcommand fails when the handler chain is stuck, not only when the port is closed.
+// Over TLS the server certificate is accepted as is and presented for client auth.
+func (s *Server) Check(ctx context.Context) error {
+	network, address := s.address()
+	host := "localhost"
+	if network == "tcp" {
+		if h, port, err := net.SplitHostPort(address); err == nil && (h == "" || net.ParseIP(h).IsUnspecified()) {
+			address = net.JoinHostPort("127.0.0.1", port)
+		}
+		host = address
+	}
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, address)
+		},
+		DisableKeepAlives: true,
+	}
+	scheme := "http"
+	if s.httpCfg.TLS.Enabled() {
+		scheme = "https"
+		transport.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: true, // the certificate names the public host, not the loopback
+			Certificates:       s.httpServer.TLSConfig.Certificates,
 		}
 	}
+	defer transport.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+host+"/api/ping", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET /api/ping: %s", resp.Status)
+	}
+	return nil
+}
 
-	return server, cleanupServer, nil
+func (s *Server) address() (network, address string) {
+	if s.httpCfg.Socket != "" {
+		return "unix", s.httpCfg.Socket
+	}
+	return "tcp", net.JoinHostPort(s.httpCfg.Host, strconv.Itoa(s.httpCfg.Port))
 }
 
-func (s *Server) Run() error {
+// start binds the port synchronously, so a busy port fails the startup instead of a goroutine.
+func (s *Server) start(context.Context) error {
 	s.registerRoutes()
+	network, address := s.address()
+
 	s.httpServer = &http.Server{
-		Addr:              ":" + strconv.Itoa(s.httpCfg.Port),
+		Addr:              address,
 		Handler:           s.Engine,
-		ReadHeaderTimeout: 1 * time.Second,
+		ReadHeaderTimeout: s.httpCfg.ReadHeaderTimeout,
+		ReadTimeout:       s.httpCfg.ReadTimeout,
+		WriteTimeout:      s.httpCfg.WriteTimeout,
+		IdleTimeout:       s.httpCfg.IdleTimeout,
+		MaxHeaderBytes:    s.httpCfg.MaxHeaderBytes,
+		// connection level noise such as TLS handshake errors of health probes
+		ErrorLog:  slog.NewLogLogger(s.log.Slog().Handler(), slog.LevelDebug),
+		Protocols: s.protocols(),
+		HTTP2: &http.HTTP2Config{
+			MaxConcurrentStreams: s.httpCfg.HTTP2.MaxConcurrentStreams,
+			MaxReadFrameSize:     s.httpCfg.HTTP2.MaxReadFrameSize,
+		},
+	}
+
+	tlsEnabled := s.httpCfg.TLS.Enabled()
+	if tlsEnabled {
+		tlsConfig, err := newTLSConfig(s.httpCfg.TLS)
+		if err != nil {
+			return err
+		}
+		s.httpServer.TLSConfig = tlsConfig
+	}
+
+	if network == "unix" {
+		_ = os.Remove(address)
 	}
-	return s.httpServer.ListenAndServe()
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return err
+	}
+
+	s.log.Info("Starting HTTP server", "address", address, "tls", tlsEnabled)
+	go func() {
+		var err error
+		if tlsEnabled {
+			// the certificate is in TLSConfig already
+			err = s.httpServer.ServeTLS(listener, "", "")
+		} else {
+			err = s.httpServer.Serve(listener)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.lifecycle.Fail("http", err)
+		}
+	}()
+	return nil
+}
+
+func (s *Server) protocols() *http.Protocols {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(s.httpCfg.HTTP2.Enabled)
+	protocols.SetUnencryptedHTTP2(s.httpCfg.HTTP2.Enabled && s.httpCfg.HTTP2.Cleartext)
+	return protocols
+}
+
+// newTLSConfig loads the key pair here rather than in ServeTLS, so unreadable or
+// mismatched files fail the start like a busy port.
+func newTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("TLS certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{cert}}
+	if cfg.ClientCAFile == "" {
+		return tlsConfig, nil
+	}
+
+	pem, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("client CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("client CA: no certificates in %s", cfg.ClientCAFile)
+	}
+
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	if cfg.ClientAuthOptional {
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return tlsConfig, nil
+}
+
+// stop waits for in-flight requests until ctx, the shutdown deadline, expires.
+func (s *Server) stop(ctx context.Context) error {
+	s.log.Info("Shutting down HTTP server...")
+	return s.httpServer.Shutdown(ctx)
 }
@@ -5,27 +5,70 @@ import (
 
 	pingController "gotemplate/internal/adapter/http/controllers/ping"
 	"gotemplate/internal/config"
+	"gotemplate/internal/infrastructure/admin"
 	httpServer "gotemplate/internal/infrastructure/http"
+	"gotemplate/pkg/lifecycle"
+	"gotemplate/pkg/logs"
+	"gotemplate/pkg/middleware"
+	"gotemplate/pkg/plugins"
 )
 
 var ConfigSet = wire.NewSet(
 	ProvideAppConfig,
 	ProvideHTTPConfig,
+	ProvideAdminConfig,
+	ProvideShutdownConfig,
 )
 
-func ProvideAppConfig(cfg *config.Config) *config.AppConfig   { return &cfg.Application }
-func ProvideHTTPConfig(cfg *config.Config) *config.HTTPConfig { return &cfg.HTTP }
+func ProvideAppConfig(cfg *config.Config) *config.AppConfig     { return &cfg.Application }
+func ProvideHTTPConfig(cfg *config.Config) *config.HTTPConfig   { return &cfg.HTTP }
+func ProvideAdminConfig(cfg *config.Config) *config.AdminConfig { return &cfg.Admin }
+func ProvideShutdownConfig(cfg *config.Config) lifecycle.Config { return cfg.Shutdown }
+
+// LoggerSet hands out the logger configured by logs.Init in main.
+var LoggerSet = wire.NewSet(
+	logs.Default,
+)
+
+// ClockSet provides the clock of the app. Return a plugins.FakeClock from ProvideClock
+// to run the app at a fixed time, NewApp installs it behind plugins.GetNow as well.
+var ClockSet = wire.NewSet(
+	ProvideClock,
+)
+
+func ProvideClock() plugins.Clock { return plugins.RealClock{} }
+
+// LifecycleSet is the manager components register their start and stop hooks with.
+var LifecycleSet = wire.NewSet(
+	lifecycle.New,
+)
 
 var ControllerSet = wire.NewSet(
 	pingController.NewController,
 )
 
+var ClientSet = wire.NewSet()
+
+// MiddlewareSet holds shared middleware state. Swap the store for a distributed one
+// to share rate limits between replicas.
+var MiddlewareSet = wire.NewSet(
+	ProvideRateLimitStore,
+)
+
+func ProvideRateLimitStore() middleware.RateLimitStore { return middleware.NewMemoryStore() }
+
 var ServerSet = wire.NewSet(
 	httpServer.NewServer,
+	admin.NewServer,
 )
 
 var AllProviders = wire.NewSet(
 	ConfigSet,
+	LoggerSet,
+	ClockSet,
+	LifecycleSet,
 	ControllerSet,
+	ClientSet,
+	MiddlewareSet,
 	ServerSet,
 )
@@ -0,0 +1,40 @@
+// Package buildinfo holds what the build stamped into the binary:
+//
+//	go build -ldflags "-X gotemplate/pkg/buildinfo.Version=v1.2.0 -X gotemplate/pkg/buildinfo.Commit=$(git rev-parse HEAD)" ./cmd
+//
+// The Dockerfile from hippo add docker passes them as build args.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+var (
+	Version = "dev"
+	Commit  = ""
+	Date    = ""
+)
+
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Date      string `json:"date,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get falls back to the VCS data the go command records for builds inside a git checkout.
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, Date: Date, GoVersion: runtime.Version()}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch {
+			case s.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = s.Value
+			case s.Key == "vcs.time" && info.Date == "":
+				info.Date = s.Value
+			}
+		}
+	}
+	return info
+}
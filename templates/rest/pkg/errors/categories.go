@@ -0,0 +1,92 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+)
+
+// gRPC status codes, values match google.golang.org/grpc/codes.
+const (
+	grpcUnknown           uint32 = 2
+	grpcInvalidArgument   uint32 = 3
+	grpcDeadlineExceeded  uint32 = 4
+	grpcNotFound          uint32 = 5
+	grpcPermissionDenied  uint32 = 7
+	grpcResourceExhausted uint32 = 8
+	grpcAborted           uint32 = 10
+	grpcInternal          uint32 = 13
+	grpcUnavailable       uint32 = 14
+	grpcUnauthenticated   uint32 = 16
+)
+
+type category struct {
+	err        error
+	httpStatus int
+	grpcCode   uint32
+}
+
+// categories is ordered from the most specific to the most generic. An error gets the
+// category of its outermost level, like Kind; errors that only wrap a category sentinel
+// with fmt.Errorf are reported as the first one matching.
+var categories = []category{
+	{ErrDataNotFound, http.StatusNotFound, grpcNotFound},
+	{ErrValidation, http.StatusBadRequest, grpcInvalidArgument},
+	{ErrConflict, http.StatusConflict, grpcAborted},
+	{ErrUnauthorized, http.StatusUnauthorized, grpcUnauthenticated},
+	{ErrPermissionDenied, http.StatusForbidden, grpcPermissionDenied},
+	{ErrRateLimited, http.StatusTooManyRequests, grpcResourceExhausted},
+	{ErrTooLarge, http.StatusRequestEntityTooLarge, grpcResourceExhausted},
+	{ErrTimeout, http.StatusGatewayTimeout, grpcDeadlineExceeded},
+	{ErrUnavailable, http.StatusServiceUnavailable, grpcUnavailable},
+	{ErrExternalService, http.StatusBadGateway, grpcUnknown},
+	{ErrSystem, http.StatusInternalServerError, grpcInternal},
+}
+
+// Categories lists every error category in matching order.
+func Categories() []error {
+	result := make([]error, len(categories))
+	for i, c := range categories {
+		result[i] = c.err
+	}
+	return result
+}
+
+func categorize(err error) category {
+	var c *customError
+	for e := err; errors.As(e, &c); e = c.err {
+		if c.baseError == nil {
+			continue
+		}
+		if cat, ok := matchCategory(c.baseError); ok {
+			return cat
+		}
+	}
+	if cat, ok := matchCategory(err); ok {
+		return cat
+	}
+	return categories[len(categories)-1]
+}
+
+func matchCategory(err error) (category, bool) {
+	for _, c := range categories {
+		if errors.Is(err, c.err) {
+			return c, true
+		}
+	}
+	return category{}, false
+}
+
+// Category returns the outermost category of err, ErrSystem when none.
+func Category(err error) error {
+	return categorize(err).err
+}
+
+// HTTPStatus is the default HTTP status for err.
+func HTTPStatus(err error) int {
+	return categorize(err).httpStatus
+}
+
+// GRPCCode is the default gRPC status code for err, convert it with codes.Code(...).
+func GRPCCode(err error) uint32 {
+	return categorize(err).grpcCode
+}
@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCategoryFollowsOutermostLevel(t *testing.T) {
+	notFound := New(ErrDataNotFound, "user_not_found", "User not found")
+	tests := []struct {
+		name   string
+		err    error
+		want   error
+		status int
+	}{
+		{"single", notFound, ErrDataNotFound, http.StatusNotFound},
+		{"rewrapped", Wrap(notFound, ErrExternalService, "profile_failed", "Profile service failed"), ErrExternalService, http.StatusBadGateway},
+		{"wrapped by fmt", fmt.Errorf("load: %w", WrapConflictError(notFound)), ErrConflict, http.StatusConflict},
+		{"sentinel only", fmt.Errorf("load: %w", ErrTimeout), ErrTimeout, http.StatusGatewayTimeout},
+		{"plain", errors.New("boom"), ErrSystem, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Category(tt.err); got != tt.want {
+				t.Errorf("Category = %v, want %v", got, tt.want)
+			}
+			if got := Kind(tt.err); got != tt.want {
+				t.Errorf("Kind = %v, want %v", got, tt.want)
+			}
+			if got := HTTPStatus(tt.err); got != tt.status {
+				t.Errorf("HTTPStatus = %d, want %d", got, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// fieldError attaches a key/value pair for logs without changing the category.
+type fieldError struct {
+	err   error
+	key   string
+	value any
+}
+
+func (f *fieldError) Error() string { return f.err.Error() }
+func (f *fieldError) Unwrap() error { return f.err }
+
+func WithField(err error, key string, value any) error {
+	if err == nil {
+		return nil
+	}
+	return &fieldError{err: err, key: key, value: value}
+}
+
+func WithFields(err error, fields map[string]any) error {
+	for key, value := range fields {
+		err = WithField(err, key, value)
+	}
+	return err
+}
+
+// Fields collects fields from the whole chain, outer values win.
+func Fields(err error) map[string]any {
+	var result map[string]any
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		f, ok := e.(*fieldError)
+		if !ok {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]any)
+		}
+		if _, exists := result[f.key]; !exists {
+			result[f.key] = f.value
+		}
+	}
+	return result
+}
+
+var stackCapture atomic.Bool
+
+// SetStackCapture turns stack capturing on wrap on or off. It is off by default because
+// runtime.Callers is not free on hot error paths.
+func SetStackCapture(enabled bool) {
+	stackCapture.Store(enabled)
+}
+
+// captureStack records the stack starting skip frames above its caller
+// unless the cause already has one.
+func captureStack(cause error, skip int) []uintptr {
+	if !stackCapture.Load() || hasStack(cause) {
+		return nil
+	}
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(skip+2, pcs)
+	return pcs[:n]
+}
+
+func hasStack(err error) bool {
+	var c *customError
+	for e := err; errors.As(e, &c); e = c.err {
+		if len(c.stack) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// StackTrace formats the innermost captured stack, empty when none was captured.
+func StackTrace(err error) string {
+	var stack []uintptr
+	var c *customError
+	for e := err; errors.As(e, &c); e = c.err {
+		if len(c.stack) > 0 {
+			stack = c.stack
+		}
+	}
+	if len(stack) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
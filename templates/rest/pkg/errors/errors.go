@@ -4,15 +4,24 @@ import (
 	"errors"
 )
 
+// customError pairs a category (baseError) with the internal cause. Code, message and
+// details are optional and meant for clients: the cause is never shown to them.
 type customError struct {
 	baseError error
 	err       error
+	code      string
+	message   string
+	details   map[string]any
+	stack     []uintptr
 }
 
 func (c *customError) Error() string {
 	if c.err != nil {
 		return c.err.Error()
 	}
+	if c.message != "" {
+		return c.message
+	}
 	return c.baseError.Error()
 }
 
@@ -39,10 +48,16 @@ var (
 	ErrExternalService  = errors.New("external_service_error")
 	ErrValidation       = errors.New("validation_error")
 	ErrPermissionDenied = errors.New("permission_denied_error")
+	ErrConflict         = errors.New("conflict_error")
+	ErrUnauthorized     = errors.New("unauthorized_error")
+	ErrRateLimited      = errors.New("rate_limited_error")
+	ErrTimeout          = errors.New("timeout_error")
+	ErrUnavailable      = errors.New("unavailable_error")
+	ErrTooLarge         = errors.New("too_large_error")
 )
 
 func wrap(currentErr, baseError error) error {
-	return &customError{baseError: baseError, err: currentErr}
+	return &customError{baseError: baseError, err: currentErr, stack: captureStack(currentErr, 2)}
 }
 
 func WrapSystemError(err error) error           { return wrap(err, ErrSystem) }
@@ -50,3 +65,81 @@ func WrapDataNotFoundError(err error) error     { return wrap(err, ErrDataNotFou
 func WrapValidationError(err error) error       { return wrap(err, ErrValidation) }
 func WrapPermissionDeniedError(err error) error { return wrap(err, ErrPermissionDenied) }
 func WrapExternalServiceError(err error) error  { return wrap(err, ErrExternalService) }
+func WrapConflictError(err error) error         { return wrap(err, ErrConflict) }
+func WrapUnauthorizedError(err error) error     { return wrap(err, ErrUnauthorized) }
+func WrapRateLimitedError(err error) error      { return wrap(err, ErrRateLimited) }
+func WrapTimeoutError(err error) error          { return wrap(err, ErrTimeout) }
+func WrapUnavailableError(err error) error      { return wrap(err, ErrUnavailable) }
+func WrapTooLargeError(err error) error         { return wrap(err, ErrTooLarge) }
+
+// New creates an error of the given category with a stable application code
+// and a message that is safe to show to clients.
+func New(kind error, code, message string) error {
+	return &customError{baseError: kind, code: code, message: message, stack: captureStack(nil, 1)}
+}
+
+// Wrap is New that keeps cause for logs and errors.Is/As.
+func Wrap(cause, kind error, code, message string) error {
+	return &customError{baseError: kind, err: cause, code: code, message: message, stack: captureStack(cause, 1)}
+}
+
+// WithDetails attaches client-visible details, e.g. the conflicting resource id.
+func WithDetails(err error, details map[string]any) error {
+	if err == nil {
+		return nil
+	}
+	return &customError{baseError: Kind(err), err: err, details: details}
+}
+
+// Kind returns the outermost category of err, ErrSystem when it has none.
+func Kind(err error) error {
+	var c *customError
+	for e := err; errors.As(e, &c); e = c.err {
+		if c.baseError != nil {
+			return c.baseError
+		}
+	}
+	return Category(err)
+}
+
+// Code returns the outermost application code, falling back to the category name.
+func Code(err error) string {
+	var c *customError
+	for e := err; errors.As(e, &c); e = c.err {
+		if c.code != "" {
+			return c.code
+		}
+	}
+	return Category(err).Error()
+}
+
+// Message returns the outermost client-safe message or an empty string.
+func Message(err error) string {
+	var c *customError
+	for e := err; errors.As(e, &c); e = c.err {
+		if c.message != "" {
+			return c.message
+		}
+	}
+	return ""
+}
+
+// Details merges details from every level of err, outer levels win.
+func Details(err error) map[string]any {
+	var result map[string]any
+	var c *customError
+	for e := err; errors.As(e, &c); e = c.err {
+		if len(c.details) == 0 {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]any)
+		}
+		for key, value := range c.details {
+			if _, exists := result[key]; !exists {
+				result[key] = value
+			}
+		}
+	}
+	return result
+}
@@ -1,15 +1,42 @@
 package ginplugins
 
 import (
+	"errors"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 
 	customErrors "gotemplate/pkg/errors"
 )
 
 func MustBindJSON(ctx *gin.Context, model any) bool {
-	if err := ctx.ShouldBindJSON(model); err != nil {
-		WrapError(customErrors.WrapValidationError(err), ctx)
+	return checkBinding(ctx, ctx.ShouldBindJSON(model))
+}
+
+func MustBindQuery(ctx *gin.Context, model any) bool {
+	return checkBinding(ctx, ctx.ShouldBindQuery(model))
+}
+
+func MustBindURI(ctx *gin.Context, model any) bool {
+	return checkBinding(ctx, ctx.ShouldBindUri(model))
+}
+
+func MustBindHeader(ctx *gin.Context, model any) bool {
+	return checkBinding(ctx, ctx.ShouldBindHeader(model))
+}
+
+func checkBinding(ctx *gin.Context, err error) bool {
+	if err == nil {
+		return true
+	}
+
+	// the body was cut by the MaxBodyBytes limit, it is not a malformed request
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		WrapError(customErrors.WrapTooLargeError(err), ctx)
 		return false
 	}
-	return true
+
+	WrapError(customErrors.WrapValidationError(NewValidationError(ctx, err)), ctx)
+	return false
 }
@@ -0,0 +1,310 @@
+package ginplugins
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	customErrors "gotemplate/pkg/errors"
+)
+
+type FilterOp string
+
+const (
+	OpEq   FilterOp = "eq"
+	OpNe   FilterOp = "ne"
+	OpIn   FilterOp = "in"
+	OpGt   FilterOp = "gt"
+	OpGte  FilterOp = "gte"
+	OpLt   FilterOp = "lt"
+	OpLte  FilterOp = "lte"
+	OpLike FilterOp = "like"
+)
+
+// ListSpec is what a list endpoint accepts. Only whitelisted sort fields and filter
+// operators get through, so repositories can map them to columns without further checks.
+type ListSpec struct {
+	DefaultLimit int
+	MaxLimit     int
+	// MaxOffset bounds ?offset and ?page, deeper pages have to use a cursor.
+	MaxOffset int
+	// DefaultSort is used without ?sort, in the same syntax: "-created_at,id".
+	DefaultSort string
+	SortFields  []string
+	Filters     map[string][]FilterOp
+}
+
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Filter is a condition from ?field=value or ?field[op]=value. Values holds one
+// element for every operator except OpIn.
+type Filter struct {
+	Field  string
+	Op     FilterOp
+	Values []string
+}
+
+func (f Filter) Value() string {
+	if len(f.Values) == 0 {
+		return ""
+	}
+	return f.Values[0]
+}
+
+// ListQuery is the parsed request of a list endpoint. With Cursor set the client pages
+// through keyset pagination and Offset is zero.
+type ListQuery struct {
+	Limit   int
+	Offset  int
+	Cursor  string
+	Sort    []Sort
+	Filters []Filter
+}
+
+// ListParams documents the query parameters of list endpoints, e.g. openapi.Route{Query: ginplugins.ListParams{}}.
+type ListParams struct {
+	Limit  int    `form:"limit" example:"20"`
+	Offset int    `form:"offset"`
+	Page   int    `form:"page"`
+	Cursor string `form:"cursor"`
+	Sort   string `form:"sort" example:"-created_at,name"`
+}
+
+var reservedListParams = []string{"limit", "offset", "page", "cursor", "sort"}
+
+// FetchLimit is how many rows a repository should load: one more than Limit tells NewList
+// whether there is a next page.
+func (q ListQuery) FetchLimit() int {
+	return q.Limit + 1
+}
+
+func (q ListQuery) Filter(field string, op FilterOp) (Filter, bool) {
+	for _, f := range q.Filters {
+		if f.Field == field && f.Op == op {
+			return f, true
+		}
+	}
+	return Filter{}, false
+}
+
+// DecodeCursor unmarshals the cursor made by EncodeCursor into v. It reports false without a cursor.
+func (q ListQuery) DecodeCursor(v any) (bool, error) {
+	if q.Cursor == "" {
+		return false, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(data, v)
+}
+
+// EncodeCursor makes an opaque cursor from the sort key of the last item of a page.
+func EncodeCursor(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("ginplugins: cursor of %T is not JSON: %v", v, err))
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseList reads pagination, sorting and filters from the query string. Violations of
+// spec are returned as a customErrors.ErrValidation carrying every offending parameter.
+func ParseList(ctx *gin.Context, spec ListSpec) (ListQuery, error) {
+	if spec.DefaultLimit <= 0 {
+		spec.DefaultLimit = 20
+	}
+	if spec.MaxLimit <= 0 {
+		spec.MaxLimit = 100
+	}
+	if spec.MaxOffset <= 0 {
+		spec.MaxOffset = 10000
+	}
+
+	values := ctx.Request.URL.Query()
+	var fieldErrs []FieldError
+	invalid := func(field, rule, param, message string) {
+		fieldErrs = append(fieldErrs, FieldError{Field: field, Rule: rule, Param: param, Message: message})
+	}
+
+	q := ListQuery{Limit: spec.DefaultLimit}
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		switch {
+		case err != nil || limit < 1:
+			invalid("limit", "min", "1", "limit must be a positive integer")
+		case limit > spec.MaxLimit:
+			invalid("limit", "max", strconv.Itoa(spec.MaxLimit), "limit must be "+strconv.Itoa(spec.MaxLimit)+" or less")
+		default:
+			q.Limit = limit
+		}
+	}
+
+	q.Cursor = values.Get("cursor")
+	if q.Cursor != "" {
+		if _, err := base64.RawURLEncoding.DecodeString(q.Cursor); err != nil {
+			invalid("cursor", "cursor", "", "cursor is malformed")
+		}
+	} else if raw := values.Get("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		switch {
+		case err != nil || offset < 0:
+			invalid("offset", "min", "0", "offset must be a non-negative integer")
+		case offset > spec.MaxOffset:
+			invalid("offset", "max", strconv.Itoa(spec.MaxOffset), "offset must be "+strconv.Itoa(spec.MaxOffset)+" or less, use the cursor")
+		default:
+			q.Offset = offset
+		}
+	} else if raw := values.Get("page"); raw != "" {
+		page, err := strconv.Atoi(raw)
+		// compared by division, (page-1)*Limit overflows for huge pages
+		maxPage := spec.MaxOffset/q.Limit + 1
+		switch {
+		case err != nil || page < 1:
+			invalid("page", "min", "1", "page must be a positive integer")
+		case page > maxPage:
+			invalid("page", "max", strconv.Itoa(maxPage), "page must be "+strconv.Itoa(maxPage)+" or less, use the cursor")
+		default:
+			q.Offset = (page - 1) * q.Limit
+		}
+	}
+
+	sort := values.Get("sort")
+	if sort == "" {
+		sort = spec.DefaultSort
+	}
+	for _, item := range strings.Split(sort, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		field, desc := strings.CutPrefix(item, "-")
+		field = strings.TrimPrefix(field, "+")
+		if !slices.Contains(spec.SortFields, field) {
+			invalid("sort", "oneof", strings.Join(spec.SortFields, " "), "cannot sort by "+field)
+			continue
+		}
+		q.Sort = append(q.Sort, Sort{Field: field, Desc: desc})
+	}
+
+	for key, raw := range values {
+		if slices.Contains(reservedListParams, key) {
+			continue
+		}
+		field, op := key, OpEq
+		if name, rest, found := strings.Cut(key, "["); found && strings.HasSuffix(rest, "]") {
+			field, op = name, FilterOp(strings.TrimSuffix(rest, "]"))
+		}
+		allowed, known := spec.Filters[field]
+		if !known {
+			// plain parameters may belong to the handler itself
+			if field != key {
+				invalid(key, "filter", "", "cannot filter by "+field)
+			}
+			continue
+		}
+		if !slices.Contains(allowed, op) {
+			invalid(key, "oneof", joinOps(allowed), "operator "+string(op)+" is not supported for "+field)
+			continue
+		}
+
+		filter := Filter{Field: field, Op: op}
+		if op == OpIn {
+			for _, value := range raw {
+				for _, item := range strings.Split(value, ",") {
+					if item = strings.TrimSpace(item); item != "" {
+						filter.Values = append(filter.Values, item)
+					}
+				}
+			}
+		} else if len(raw) > 1 {
+			invalid(key, "single", "", key+" is given more than once")
+			continue
+		} else {
+			filter.Values = raw
+		}
+		q.Filters = append(q.Filters, filter)
+	}
+	// map iteration order is random, keep the query deterministic for repositories and tests
+	slices.SortFunc(q.Filters, func(a, b Filter) int {
+		return strings.Compare(a.Field+"["+string(a.Op), b.Field+"["+string(b.Op))
+	})
+
+	if len(fieldErrs) > 0 {
+		slices.SortStableFunc(fieldErrs, func(a, b FieldError) int { return strings.Compare(a.Field, b.Field) })
+		return ListQuery{}, customErrors.WrapValidationError(&ValidationError{Fields: fieldErrs})
+	}
+	return q, nil
+}
+
+// MustParseList is ParseList that writes the error response, like MustBindQuery.
+func MustParseList(ctx *gin.Context, spec ListSpec) (ListQuery, bool) {
+	q, err := ParseList(ctx, spec)
+	if err != nil {
+		WrapError(err, ctx)
+		return ListQuery{}, false
+	}
+	return q, true
+}
+
+func joinOps(ops []FilterOp) string {
+	names := make([]string, len(ops))
+	for i, op := range ops {
+		names[i] = string(op)
+	}
+	return strings.Join(names, " ")
+}
+
+// List is the envelope of list responses.
+type List[T any] struct {
+	Items []T      `json:"items" binding:"required"`
+	Page  PageInfo `json:"page" binding:"required"`
+}
+
+type PageInfo struct {
+	Limit      int    `json:"limit" binding:"required"`
+	Offset     int    `json:"offset,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Next is the URL of the next page, absent on the last one.
+	Next string `json:"next,omitempty"`
+}
+
+// NewList builds the envelope from up to q.FetchLimit() items. cursor returns the sort key
+// of an item for keyset pagination; with nil cursor the next page is addressed by offset.
+func NewList[T any](ctx *gin.Context, q ListQuery, items []T, cursor func(item T) any) List[T] {
+	if items == nil {
+		items = []T{}
+	}
+	list := List[T]{Page: PageInfo{Limit: q.Limit, Offset: q.Offset}}
+	if len(items) <= q.Limit {
+		list.Items = items
+		return list
+	}
+
+	list.Items = items[:q.Limit]
+	next := ctx.Request.URL.Query()
+	next.Del("page")
+	if cursor != nil {
+		list.Page.NextCursor = EncodeCursor(cursor(list.Items[q.Limit-1]))
+		next.Del("offset")
+		next.Set("cursor", list.Page.NextCursor)
+	} else {
+		next.Set("offset", strconv.Itoa(q.Offset+q.Limit))
+	}
+	next.Set("limit", strconv.Itoa(q.Limit))
+
+	link := url.URL{Path: ctx.Request.URL.Path, RawQuery: next.Encode()}
+	list.Page.Next = link.String()
+	ctx.Header("Link", "<"+list.Page.Next+`>; rel="next"`)
+	return list
+}
@@ -0,0 +1,118 @@
+package ginplugins
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	customErrors "gotemplate/pkg/errors"
+)
+
+var usersSpec = ListSpec{
+	MaxLimit:    50,
+	DefaultSort: "-created_at",
+	SortFields:  []string{"created_at", "name"},
+	Filters: map[string][]FilterOp{
+		"status":     {OpEq, OpIn},
+		"created_at": {OpGte, OpLt},
+		"name":       {OpLike},
+	},
+}
+
+func listContext(target string) *gin.Context {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("GET", target, nil)
+	return ctx
+}
+
+func TestParseList(t *testing.T) {
+	q, err := ParseList(listContext("/users?limit=10&page=3&sort=name,-created_at&status[in]=new,active&created_at[gte]=2024-01-01&name[like]=ann&q=x"), usersSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ListQuery{
+		Limit:  10,
+		Offset: 20,
+		Sort:   []Sort{{Field: "name"}, {Field: "created_at", Desc: true}},
+		Filters: []Filter{
+			{Field: "created_at", Op: OpGte, Values: []string{"2024-01-01"}},
+			{Field: "name", Op: OpLike, Values: []string{"ann"}},
+			{Field: "status", Op: OpIn, Values: []string{"new", "active"}},
+		},
+	}
+	if !reflect.DeepEqual(q, want) {
+		t.Errorf("got %+v\nwant %+v", q, want)
+	}
+
+	q, err = ParseList(listContext("/users"), usersSpec)
+	if err != nil || q.Limit != 20 || !reflect.DeepEqual(q.Sort, []Sort{{Field: "created_at", Desc: true}}) {
+		t.Errorf("defaults: %+v, %v", q, err)
+	}
+}
+
+func TestParseListRejects(t *testing.T) {
+	_, err := ParseList(listContext("/users?limit=500&sort=password&status[gte]=a&email[eq]=x&cursor=%25"), usersSpec)
+	if !errors.Is(err, customErrors.ErrValidation) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	var validationErr *ValidationError
+	errors.As(err, &validationErr)
+	var fields []string
+	for _, f := range validationErr.Fields {
+		fields = append(fields, f.Field)
+	}
+	if want := []string{"cursor", "email[eq]", "limit", "sort", "status[gte]"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields %v, want %v", fields, want)
+	}
+}
+
+func TestParseListBoundsOffset(t *testing.T) {
+	for _, target := range []string{
+		"/users?offset=10001",
+		"/users?offset=99999999999999999999",
+		"/users?page=9223372036854775807",
+		"/users?limit=50&page=202",
+	} {
+		q, err := ParseList(listContext(target), usersSpec)
+		if !errors.Is(err, customErrors.ErrValidation) {
+			t.Errorf("%s: expected a validation error, got %+v, %v", target, q, err)
+		}
+	}
+
+	q, err := ParseList(listContext("/users?limit=50&page=201"), usersSpec)
+	if err != nil || q.Offset != 10000 {
+		t.Errorf("last page: %+v, %v", q, err)
+	}
+}
+
+func TestNewList(t *testing.T) {
+	type user struct{ ID int }
+	ctx := listContext("/users?limit=2&status=new")
+	q, _ := ParseList(ctx, usersSpec)
+
+	list := NewList(ctx, q, []user{{1}, {2}, {3}}, func(u user) any { return u.ID })
+	if len(list.Items) != 2 || list.Page.NextCursor == "" {
+		t.Fatalf("unexpected page %+v", list)
+	}
+
+	ctx = listContext(list.Page.Next)
+	q, err := ParseList(ctx, usersSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var after int
+	if ok, err := q.DecodeCursor(&after); !ok || err != nil || after != 2 {
+		t.Errorf("cursor decoded to %d, %v, %v", after, ok, err)
+	}
+	if f, ok := q.Filter("status", OpEq); !ok || f.Value() != "new" {
+		t.Error("filters are not kept in the next link")
+	}
+
+	list = NewList(ctx, q, []user{{3}}, nil)
+	if list.Page.Next != "" || ctx.Writer.Header().Get("Link") != "" {
+		t.Errorf("last page has a next link: %+v", list.Page)
+	}
+}
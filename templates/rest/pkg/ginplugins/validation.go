@@ -0,0 +1,169 @@
+package ginplugins
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ru"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	ruTranslations "github.com/go-playground/validator/v10/translations/ru"
+)
+
+const defaultLocale = "en"
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+var (
+	validationOnce sync.Once
+	validate       *validator.Validate
+	translators    *ut.UniversalTranslator
+)
+
+// InitValidation configures gin's validator: JSON/form/uri/header tag names in errors
+// and en/ru translations. It is safe to call more than once.
+func InitValidation() {
+	validationOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			panic("gin validator engine is not go-playground/validator")
+		}
+		v.RegisterTagNameFunc(fieldName)
+
+		translators = ut.New(en.New(), en.New(), ru.New())
+		enTrans, _ := translators.GetTranslator("en")
+		ruTrans, _ := translators.GetTranslator("ru")
+		if err := enTranslations.RegisterDefaultTranslations(v, enTrans); err != nil {
+			panic(err)
+		}
+		if err := ruTranslations.RegisterDefaultTranslations(v, ruTrans); err != nil {
+			panic(err)
+		}
+		validate = v
+	})
+}
+
+// RegisterValidation adds a custom binding rule. Messages are keyed by locale and use
+// {0} for the field name and {1} for the rule parameter, e.g. {"en": "{0} must be a valid INN"}.
+func RegisterValidation(tag string, fn validator.Func, messages map[string]string) error {
+	InitValidation()
+	if err := validate.RegisterValidation(tag, fn); err != nil {
+		return err
+	}
+
+	for locale, message := range messages {
+		trans, found := translators.GetTranslator(locale)
+		if !found {
+			return errors.New("unsupported validation locale " + locale)
+		}
+		err := validate.RegisterTranslation(tag, trans,
+			func(t ut.Translator) error { return t.Add(tag, message, true) },
+			func(t ut.Translator, fe validator.FieldError) string {
+				msg, err := t.T(tag, fe.Field(), fe.Param())
+				if err != nil {
+					return fe.Error()
+				}
+				return msg
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "uri", "header"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+func translator(ctx *gin.Context) ut.Translator {
+	InitValidation()
+	for _, lang := range strings.Split(ctx.GetHeader("Accept-Language"), ",") {
+		lang, _, _ = strings.Cut(strings.TrimSpace(lang), ";")
+		lang, _, _ = strings.Cut(lang, "-")
+		if trans, found := translators.GetTranslator(strings.ToLower(lang)); found {
+			return trans
+		}
+	}
+	trans, _ := translators.GetTranslator(defaultLocale)
+	return trans
+}
+
+// NewValidationError converts binding errors into per-field errors translated
+// for the request's Accept-Language.
+func NewValidationError(ctx *gin.Context, err error) *ValidationError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		trans := translator(ctx)
+		result := &ValidationError{Fields: make([]FieldError, 0, len(validationErrs))}
+		for _, fe := range validationErrs {
+			result.Fields = append(result.Fields, FieldError{
+				Field:   fieldPath(fe.Namespace()),
+				Rule:    fe.Tag(),
+				Param:   fe.Param(),
+				Message: fe.Translate(trans),
+			})
+		}
+		return result
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return &ValidationError{Fields: []FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Param:   typeErr.Type.String(),
+			Message: typeErr.Field + " must be " + typeErr.Type.String(),
+		}}}
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return &ValidationError{Fields: []FieldError{{Field: "body", Rule: "json", Message: "malformed JSON: " + syntaxErr.Error()}}}
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return &ValidationError{Fields: []FieldError{{Field: "body", Rule: "json", Message: "request body is empty or truncated"}}}
+	}
+
+	return &ValidationError{Fields: []FieldError{{Field: "", Rule: "bind", Message: err.Error()}}}
+}
+
+// fieldPath strips the root struct name: "CreateUser.address.city" -> "address.city".
+func fieldPath(namespace string) string {
+	if _, rest, found := strings.Cut(namespace, "."); found {
+		return rest
+	}
+	return namespace
+}
@@ -0,0 +1,57 @@
+package ginplugins
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestFieldName(t *testing.T) {
+	type request struct {
+		Name    string `json:"name,omitempty"`
+		Page    int    `json:"-" form:"page"`
+		ID      string `uri:"id"`
+		Token   string `header:"X-Token"`
+		Skipped string `json:"-"`
+		Plain   string
+	}
+
+	tests := map[string]string{
+		"Name":    "name",
+		"Page":    "page",
+		"ID":      "id",
+		"Token":   "X-Token",
+		"Skipped": "Skipped",
+		"Plain":   "Plain",
+	}
+	typ := reflect.TypeFor[request]()
+	for field, want := range tests {
+		f, _ := typ.FieldByName(field)
+		if got := fieldName(f); got != want {
+			t.Errorf("fieldName(%s) = %q, want %q", field, got, want)
+		}
+	}
+}
+
+func TestNewValidationErrorUsesTagNames(t *testing.T) {
+	InitValidation()
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("GET", "/users?limit=0", nil)
+
+	var query struct {
+		Page  int `json:"-" form:"page" binding:"required"`
+		Limit int `form:"limit" binding:"min=1"`
+	}
+	err := ctx.ShouldBindQuery(&query)
+	if err == nil {
+		t.Fatal("binding succeeded")
+	}
+
+	got := NewValidationError(ctx, err)
+	if len(got.Fields) != 2 || got.Fields[0].Field != "page" || got.Fields[1].Field != "limit" {
+		t.Errorf("fields %+v, want page and limit", got.Fields)
+	}
+}
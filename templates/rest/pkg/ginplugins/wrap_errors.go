@@ -3,31 +3,118 @@ package ginplugins
 import (
 	"errors"
 	"net/http"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 
 	customErrors "gotemplate/pkg/errors"
+	"gotemplate/pkg/logs"
 )
 
-func WrapError(err error, ginContext *gin.Context) {
-	statusCode := http.StatusInternalServerError
-	errType := customErrors.ErrSystem
-	switch {
-	case errors.Is(err, customErrors.ErrDataNotFound):
-		errType = customErrors.ErrDataNotFound
-		statusCode = http.StatusNotFound
-	case errors.Is(err, customErrors.ErrValidation):
-		errType = customErrors.ErrValidation
-		statusCode = http.StatusBadRequest
-	case errors.Is(err, customErrors.ErrExternalService):
-		errType = customErrors.ErrExternalService
-		statusCode = http.StatusExpectationFailed
-	case errors.Is(err, customErrors.ErrSystem):
-		errType = customErrors.ErrSystem
-	case errors.Is(err, customErrors.ErrPermissionDenied):
-		errType = customErrors.ErrPermissionDenied
-		statusCode = http.StatusForbidden
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 error body extended with the application error code.
+type Problem struct {
+	Type     string         `json:"type" binding:"required"`
+	Title    string         `json:"title" binding:"required"`
+	Status   int            `json:"status" binding:"required"`
+	Detail   string         `json:"detail,omitempty"`
+	Instance string         `json:"instance,omitempty"`
+	Code     string         `json:"code" binding:"required"`
+	Details  map[string]any `json:"details,omitempty"`
+	Errors   []FieldError   `json:"errors,omitempty"`
+	// Internal carries the raw error chain and is filled in debug mode only.
+	Internal string `json:"internal,omitempty"`
+}
+
+type errorStatus struct {
+	err    error
+	status int
+}
+
+var (
+	statusMu           sync.RWMutex
+	statusOverrides    []errorStatus
+	problemTypeBaseURI string
+)
+
+// SetErrorStatus overrides the default HTTP status of an error category (see customErrors.HTTPStatus)
+// or maps an additional sentinel error. Overrides are checked before the defaults.
+func SetErrorStatus(kind error, status int) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+
+	for i := range statusOverrides {
+		if statusOverrides[i].err == kind {
+			statusOverrides[i].status = status
+			return
+		}
+	}
+	statusOverrides = append(statusOverrides, errorStatus{kind, status})
+}
+
+// SetProblemTypeBaseURI makes problem types resolvable, e.g. https://docs.example.com/errors/
+// turns code "invoice_paid" into https://docs.example.com/errors/invoice_paid.
+// Without it every problem has type "about:blank".
+func SetProblemTypeBaseURI(uri string) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	problemTypeBaseURI = uri
+}
+
+// ErrorStatus returns the error category err belongs to and the HTTP status WrapError uses for it.
+func ErrorStatus(err error) (error, int) {
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+
+	for _, mapping := range statusOverrides {
+		if errors.Is(err, mapping.err) {
+			return mapping.err, mapping.status
+		}
+	}
+	return customErrors.Category(err), customErrors.HTTPStatus(err)
+}
+
+// NewProblem builds the client-facing body for err. Internal error strings only
+// make it into the body when debug is set.
+func NewProblem(err error, requestID string, debug bool) Problem {
+	_, statusCode := ErrorStatus(err)
+
+	problem := Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(statusCode),
+		Status:   statusCode,
+		Detail:   customErrors.Message(err),
+		Instance: requestID,
+		Code:     customErrors.Code(err),
+		Details:  customErrors.Details(err),
+	}
+
+	statusMu.RLock()
+	if problemTypeBaseURI != "" {
+		problem.Type = problemTypeBaseURI + problem.Code
 	}
+	statusMu.RUnlock()
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		problem.Errors = validationErr.Fields
+		if problem.Detail == "" {
+			problem.Detail = validationErr.Error()
+		}
+	}
+	if debug {
+		problem.Internal = err.Error()
+	}
+	return problem
+}
+
+func WrapError(err error, ginContext *gin.Context) {
+	errType, _ := ErrorStatus(err)
+	problem := NewProblem(err, logs.RequestIDFromContext(ginContext.Request.Context()), gin.IsDebugging())
+
 	ginContext.Writer.Header().Set("X-Error-Type", errType.Error())
-	ginContext.JSON(statusCode, gin.H{"error": err.Error()})
+	// gin keeps an already set Content-Type, so the body is still JSON-encoded
+	ginContext.Writer.Header().Set("Content-Type", ProblemContentType)
+	ginContext.JSON(problem.Status, problem)
 }
@@ -0,0 +1,174 @@
+package ginplugins
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	customErrors "gotemplate/pkg/errors"
+)
+
+// restoreProblemSettings undoes SetErrorStatus and SetProblemTypeBaseURI calls of a test.
+func restoreProblemSettings(t *testing.T) {
+	t.Helper()
+	statusMu.Lock()
+	overrides, baseURI := append([]errorStatus(nil), statusOverrides...), problemTypeBaseURI
+	statusMu.Unlock()
+	t.Cleanup(func() {
+		statusMu.Lock()
+		defer statusMu.Unlock()
+		statusOverrides, problemTypeBaseURI = overrides, baseURI
+	})
+}
+
+// renderError runs WrapError and decodes the response.
+func renderError(t *testing.T, err error) (*httptest.ResponseRecorder, Problem) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(rec)
+	ctx.Request = httptest.NewRequest("GET", "/invoices/1", nil)
+
+	WrapError(err, ctx)
+
+	var problem Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("body %q: %v", rec.Body, err)
+	}
+	return rec, problem
+}
+
+func TestWrapErrorStatusByCategory(t *testing.T) {
+	cause := errors.New("pq: duplicate key")
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		kind   error
+	}{
+		{"not found", customErrors.WrapDataNotFoundError(cause), http.StatusNotFound, customErrors.ErrDataNotFound},
+		{"validation", customErrors.WrapValidationError(cause), http.StatusBadRequest, customErrors.ErrValidation},
+		{"conflict", customErrors.WrapConflictError(cause), http.StatusConflict, customErrors.ErrConflict},
+		{"unauthorized", customErrors.WrapUnauthorizedError(cause), http.StatusUnauthorized, customErrors.ErrUnauthorized},
+		{"permission denied", customErrors.WrapPermissionDeniedError(cause), http.StatusForbidden, customErrors.ErrPermissionDenied},
+		{"rate limited", customErrors.WrapRateLimitedError(cause), http.StatusTooManyRequests, customErrors.ErrRateLimited},
+		{"too large", customErrors.WrapTooLargeError(cause), http.StatusRequestEntityTooLarge, customErrors.ErrTooLarge},
+		{"timeout", customErrors.WrapTimeoutError(cause), http.StatusGatewayTimeout, customErrors.ErrTimeout},
+		{"unavailable", customErrors.WrapUnavailableError(cause), http.StatusServiceUnavailable, customErrors.ErrUnavailable},
+		{"external service", customErrors.WrapExternalServiceError(cause), http.StatusBadGateway, customErrors.ErrExternalService},
+		{"system", customErrors.WrapSystemError(cause), http.StatusInternalServerError, customErrors.ErrSystem},
+		{"plain", cause, http.StatusInternalServerError, customErrors.ErrSystem},
+		{"wrapped by fmt", fmt.Errorf("save: %w", customErrors.WrapConflictError(cause)), http.StatusConflict, customErrors.ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, problem := renderError(t, tt.err)
+			if rec.Code != tt.status || problem.Status != tt.status {
+				t.Errorf("status %d, body status %d, want %d", rec.Code, problem.Status, tt.status)
+			}
+			if problem.Title != http.StatusText(tt.status) || problem.Code != tt.kind.Error() {
+				t.Errorf("title %q, code %q", problem.Title, problem.Code)
+			}
+			if got := rec.Header().Get("X-Error-Type"); got != tt.kind.Error() {
+				t.Errorf("X-Error-Type %q, want %q", got, tt.kind)
+			}
+			if got := rec.Header().Get("Content-Type"); got != ProblemContentType {
+				t.Errorf("Content-Type %q", got)
+			}
+		})
+	}
+}
+
+func TestSetErrorStatus(t *testing.T) {
+	restoreProblemSettings(t)
+	errPaymentRequired := errors.New("payment_required")
+
+	SetErrorStatus(customErrors.ErrConflict, http.StatusUnprocessableEntity)
+	SetErrorStatus(errPaymentRequired, http.StatusTeapot)
+	SetErrorStatus(errPaymentRequired, http.StatusPaymentRequired)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		kind   error
+	}{
+		{"category override", customErrors.WrapConflictError(errors.New("version mismatch")), http.StatusUnprocessableEntity, customErrors.ErrConflict},
+		{"sentinel", customErrors.Wrap(errPaymentRequired, customErrors.ErrPermissionDenied, "card_declined", "Card declined"), http.StatusPaymentRequired, errPaymentRequired},
+		{"default", customErrors.WrapTooLargeError(errors.New("body")), http.StatusRequestEntityTooLarge, customErrors.ErrTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, status := ErrorStatus(tt.err)
+			if kind != tt.kind || status != tt.status {
+				t.Errorf("ErrorStatus = %v, %d, want %v, %d", kind, status, tt.kind, tt.status)
+			}
+			if rec, _ := renderError(t, tt.err); rec.Code != tt.status {
+				t.Errorf("WrapError status %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+	if len(statusOverrides) != 2 {
+		t.Errorf("%d overrides, a repeated kind must replace its status", len(statusOverrides))
+	}
+}
+
+func TestProblemTypeBaseURI(t *testing.T) {
+	restoreProblemSettings(t)
+	err := customErrors.New(customErrors.ErrConflict, "invoice_paid", "Invoice is already paid")
+
+	if problem := NewProblem(err, "req-1", false); problem.Type != "about:blank" {
+		t.Errorf("type %q without a base URI", problem.Type)
+	}
+	SetProblemTypeBaseURI("https://docs.example.com/errors/")
+	problem := NewProblem(err, "req-1", false)
+	if problem.Type != "https://docs.example.com/errors/invoice_paid" {
+		t.Errorf("type %q", problem.Type)
+	}
+	if problem.Detail != "Invoice is already paid" || problem.Instance != "req-1" {
+		t.Errorf("detail %q, instance %q", problem.Detail, problem.Instance)
+	}
+}
+
+func TestInternalErrorsStaySafe(t *testing.T) {
+	err := customErrors.WrapSystemError(errors.New("pq: password authentication failed for user billing"))
+
+	problem := NewProblem(err, "req-1", false)
+	if problem.Detail != "" || problem.Internal != "" || problem.Code != customErrors.ErrSystem.Error() {
+		t.Errorf("problem %+v exposes the cause", problem)
+	}
+	if rec, _ := renderError(t, err); strings.Contains(rec.Body.String(), "password") {
+		t.Errorf("response %s exposes the cause", rec.Body)
+	}
+
+	if debug := NewProblem(err, "req-1", true); !strings.Contains(debug.Internal, "password authentication failed") {
+		t.Errorf("debug internal %q, want the error chain", debug.Internal)
+	}
+
+	safe := customErrors.Wrap(err, customErrors.ErrUnavailable, "billing_down", "Billing is temporarily unavailable")
+	if problem := NewProblem(safe, "req-1", false); problem.Detail != "Billing is temporarily unavailable" || problem.Code != "billing_down" {
+		t.Errorf("detail %q, code %q", problem.Detail, problem.Code)
+	}
+}
+
+func TestProblemCarriesValidationErrors(t *testing.T) {
+	validation := &ValidationError{Fields: []FieldError{{Field: "page", Rule: "required", Message: "page is a required field"}}}
+	problem := NewProblem(customErrors.WrapValidationError(validation), "", false)
+
+	if problem.Status != http.StatusBadRequest || len(problem.Errors) != 1 || problem.Errors[0].Field != "page" {
+		t.Errorf("problem %+v", problem)
+	}
+	if problem.Detail != "page is a required field" {
+		t.Errorf("detail %q, want the field messages", problem.Detail)
+	}
+}
@@ -38,48 +38,50 @@ func (t *Transport) doRequest(ctx context.Context, uri, method string, body []by
 
 	fullURL := t.BaseURL + uri
 
+	logURL := logs.RedactURL(fullURL)
+
 	logs.InfoCtx(ctx, "Preparing HTTP request",
 		"method", method,
-		"url", fullURL,
+		"url", logURL,
 		"bodySize", len(body),
 		"hasQueryString", queryString != nil && len(*queryString) > 0,
 	)
 
 	logs.DebugCtx(ctx, "Request details",
 		"method", method,
-		"url", fullURL,
-		"body", string(body),
+		"url", logURL,
+		"body", logs.RedactJSON(body),
 		"queryString", queryString,
 		"extraHeaders", extraHeaders,
 	)
 
 	req, err := http.NewRequestWithContext(ctx, method, fullURL, bytes.NewBuffer(body))
 	if err != nil {
-		logs.PanicCtx(ctx, "Failed to create HTTP request", "error", err, "url", fullURL, "method", method)
+		logs.PanicCtx(ctx, "Failed to create HTTP request", "error", err, "url", logURL, "method", method)
 		return nil, 0, customErrors.WrapSystemError(err)
 	}
 
 	for _, header := range *extraHeaders {
 		req.Header.Set(header.Key, header.Value)
-		logs.DebugCtx(ctx, "Setting extra header", "key", header.Key, "value", header.Value)
+		logs.DebugCtx(ctx, "Setting extra header", "key", header.Key, "value", logs.Lazy(func() string { return logs.RedactHeader(header.Key, header.Value) }))
 	}
 	for _, header := range t.Headers {
 		req.Header.Set(header.Key, header.Value)
-		logs.DebugCtx(ctx, "Setting transport header", "key", header.Key, "value", header.Value)
+		logs.DebugCtx(ctx, "Setting transport header", "key", header.Key, "value", logs.Lazy(func() string { return logs.RedactHeader(header.Key, header.Value) }))
 	}
 
 	if queryString != nil && len(*queryString) > 0 {
 		query := req.URL.Query()
 		for key, value := range *queryString {
 			query.Add(key, fmt.Sprintf("%v", value))
-			logs.DebugCtx(ctx, "Adding query parameter", "key", key, "value", value)
+			logs.DebugCtx(ctx, "Adding query parameter", "key", key, "value", logs.RedactValue(key, value))
 		}
 		req.URL.RawQuery = query.Encode()
-		logs.DebugCtx(ctx, "Final query string", "rawQuery", req.URL.RawQuery)
+		logs.DebugCtx(ctx, "Final query string", "rawQuery", logs.Lazy(func() string { return logs.RedactQuery(req.URL.RawQuery) }))
 	}
 
 	startTime := time.Now()
-	logs.InfoCtx(ctx, "Sending HTTP request", "method", method, "url", fullURL)
+	logs.InfoCtx(ctx, "Sending HTTP request", "method", method, "url", logURL)
 
 	resp, err := t.HTTPClient.Do(req)
 	duration := time.Since(startTime)
@@ -87,7 +89,7 @@ func (t *Transport) doRequest(ctx context.Context, uri, method string, body []by
 	if err != nil {
 		logs.ErrorCtx(ctx, "HTTP request failed",
 			"error", err,
-			"url", fullURL,
+			"url", logURL,
 			"method", method,
 			"duration", duration,
 		)
@@ -99,7 +101,7 @@ func (t *Transport) doRequest(ctx context.Context, uri, method string, body []by
 		"statusCode", resp.StatusCode,
 		"status", resp.Status,
 		"duration", duration,
-		"url", fullURL,
+		"url", logURL,
 	)
 
 	content, err := io.ReadAll(resp.Body)
@@ -107,7 +109,7 @@ func (t *Transport) doRequest(ctx context.Context, uri, method string, body []by
 		logs.ErrorCtx(ctx, "Failed to read response body",
 			"error", err,
 			"statusCode", resp.StatusCode,
-			"url", fullURL,
+			"url", logURL,
 		)
 		return nil, resp.StatusCode, customErrors.WrapSystemError(err)
 	}
@@ -115,23 +117,23 @@ func (t *Transport) doRequest(ctx context.Context, uri, method string, body []by
 	logs.DebugCtx(ctx, "Response body received",
 		"statusCode", resp.StatusCode,
 		"contentSize", len(content),
-		"content", string(content),
+		"content", logs.RedactJSON(content),
 	)
 	switch {
 	case resp.StatusCode >= http.StatusInternalServerError:
 		logs.ErrorCtx(ctx, "HTTP request returned server error status",
 			"statusCode", resp.StatusCode,
 			"status", resp.Status,
-			"url", fullURL,
-			"responseBody", string(content),
+			"url", logURL,
+			"responseBody", logs.RedactJSON(content),
 		)
 		err = customErrors.WrapSystemError(errors.New(string(content)))
 	case resp.StatusCode >= http.StatusBadRequest:
 		logs.WarnCtx(ctx, "HTTP request returned client error status",
 			"statusCode", resp.StatusCode,
 			"status", resp.Status,
-			"url", fullURL,
-			"responseBody", string(content),
+			"url", logURL,
+			"responseBody", logs.RedactJSON(content),
 		)
 		err = customErrors.WrapExternalServiceError(errors.New(string(content)))
 	default:
@@ -139,7 +141,7 @@ func (t *Transport) doRequest(ctx context.Context, uri, method string, body []by
 			"statusCode", resp.StatusCode,
 			"duration", duration,
 			"responseSize", len(content),
-			"url", fullURL,
+			"url", logURL,
 		)
 	}
 
@@ -153,30 +155,57 @@ func (t *Transport) Get(ctx context.Context, url string, queryString *JsonMap) (
 
 func (t *Transport) Post(ctx context.Context, url string, body any) ([]byte, int, error) {
 	logs.InfoCtx(ctx, "Executing POST request", "url", url, "bodyType", fmt.Sprintf("%T", body))
+	return t.Do(ctx, http.MethodPost, url, body, nil)
+}
 
-	var requestBody []byte
-	var err error
+func (t *Transport) Put(ctx context.Context, url string, body any) ([]byte, int, error) {
+	logs.InfoCtx(ctx, "Executing PUT request", "url", url, "bodyType", fmt.Sprintf("%T", body))
+	return t.Do(ctx, http.MethodPut, url, body, nil)
+}
+
+func (t *Transport) Patch(ctx context.Context, url string, body any) ([]byte, int, error) {
+	logs.InfoCtx(ctx, "Executing PATCH request", "url", url, "bodyType", fmt.Sprintf("%T", body))
+	return t.Do(ctx, http.MethodPatch, url, body, nil)
+}
+
+func (t *Transport) Delete(ctx context.Context, url string, queryString *JsonMap) ([]byte, int, error) {
+	logs.InfoCtx(ctx, "Executing DELETE request", "url", url, "hasQueryString", queryString != nil && len(*queryString) > 0)
+	return t.Do(ctx, http.MethodDelete, url, nil, queryString)
+}
+
+// Do sends a request with an arbitrary method. Body is encoded the same way as in Post,
+// a nil body sends no payload.
+func (t *Transport) Do(ctx context.Context, method, url string, body any, queryString *JsonMap, headers ...Header) ([]byte, int, error) {
+	requestBody, bodyHeaders, err := encodeBody(body)
+	if err != nil {
+		logs.PanicCtx(ctx, "Failed to prepare request body", "error", err, "url", url, "method", method)
+		return nil, 0, customErrors.WrapSystemError(err)
+	}
+	headers = append(bodyHeaders, headers...)
+	return t.doRequest(ctx, url, method, requestBody, queryString, &headers)
+}
+
+func encodeBody(body any) ([]byte, []Header, error) {
 	headers := make([]Header, 0)
 
 	switch v := body.(type) {
+	case nil:
+		return nil, headers, nil
 	case *JsonMap:
-		requestBody, err = json.Marshal(*v)
-		headers = append(headers, Header{"Content-Type", "application/json"})
+		requestBody, err := json.Marshal(*v)
+		return requestBody, append(headers, Header{"Content-Type", "application/json"}), err
 	case JsonMap:
-		requestBody, err = json.Marshal(v)
-		headers = append(headers, Header{"Content-Type", "application/json"})
+		requestBody, err := json.Marshal(v)
+		return requestBody, append(headers, Header{"Content-Type", "application/json"}), err
 	case map[string]any:
-		requestBody, err = json.Marshal(v)
-		headers = append(headers, Header{"Content-Type", "application/json"})
+		requestBody, err := json.Marshal(v)
+		return requestBody, append(headers, Header{"Content-Type", "application/json"}), err
 	case []byte:
-		requestBody = v
+		return v, headers, nil
+	case json.Marshaler:
+		requestBody, err := v.MarshalJSON()
+		return requestBody, append(headers, Header{"Content-Type", "application/json"}), err
 	default:
-		err = fmt.Errorf("unsupported body type %T", body)
-	}
-
-	if err != nil {
-		logs.PanicCtx(ctx, "Failed to prepare POST request body", "error", err, "url", url)
-		return nil, 0, customErrors.WrapSystemError(err)
+		return nil, nil, fmt.Errorf("unsupported body type %T", body)
 	}
-	return t.doRequest(ctx, url, http.MethodPost, requestBody, nil, &headers)
 }
@@ -0,0 +1,214 @@
+// Package lifecycle starts and stops application components in order.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gotemplate/pkg/logs"
+)
+
+// Priorities order hooks: lower values start first and stop last.
+const (
+	PriorityControl  = -100 // admin socket: available during the whole shutdown
+	PriorityResource = 0    // databases, caches, clients
+	PriorityServer   = 100  // servers and consumers: stop taking work first
+)
+
+type Hook struct {
+	Name     string
+	Priority int
+	// OnStart must not block: run servers and consumers in a goroutine and report
+	// their failures through Manager.Fail.
+	OnStart func(ctx context.Context) error
+	// OnStop drains in-flight work and releases resources before ctx expires.
+	OnStop func(ctx context.Context) error
+}
+
+type Config struct {
+	// PreStopDelay keeps serving after readiness turns false, so load balancers notice first.
+	PreStopDelay time.Duration
+	// Timeout bounds the whole shutdown, including PreStopDelay.
+	Timeout time.Duration
+}
+
+type Manager struct {
+	cfg Config
+	log *logs.Logger
+
+	mu       sync.Mutex
+	hooks    []Hook
+	started  []Hook
+	ready    atomic.Bool
+	stopping atomic.Bool
+	failed   chan error
+	failOnce sync.Once
+}
+
+func New(cfg Config, logger *logs.Logger) *Manager {
+	return &Manager{cfg: cfg, log: logger.Named("lifecycle"), failed: make(chan error, 1)}
+}
+
+// Append registers a hook. Hooks with equal priority keep registration order.
+func (m *Manager) Append(hook Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook)
+}
+
+// Start runs OnStart hooks by priority and marks the application ready. When a hook fails,
+// the already started ones are stopped and the error is returned.
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	hooks := append([]Hook(nil), m.hooks...)
+	m.mu.Unlock()
+	sort.SliceStable(hooks, func(i, j int) bool { return hooks[i].Priority < hooks[j].Priority })
+
+	for _, hook := range hooks {
+		if hook.OnStart != nil {
+			if err := hook.OnStart(ctx); err != nil {
+				m.Stop()
+				return fmt.Errorf("start %s: %w", hook.Name, err)
+			}
+		}
+		m.mu.Lock()
+		m.started = append(m.started, hook)
+		m.mu.Unlock()
+	}
+
+	m.ready.Store(true)
+	return nil
+}
+
+// Ready is true between a successful Start and the beginning of Stop.
+func (m *Manager) Ready() bool {
+	return m.ready.Load()
+}
+
+func (m *Manager) Stopping() bool {
+	return m.stopping.Load()
+}
+
+// Fail reports a component that stopped working, e.g. a server that could not serve.
+// Only the first failure is kept.
+func (m *Manager) Fail(name string, err error) {
+	m.failOnce.Do(func() {
+		m.failed <- fmt.Errorf("%s: %w", name, err)
+	})
+}
+
+// Failed delivers the first error reported through Fail.
+func (m *Manager) Failed() <-chan error {
+	return m.failed
+}
+
+type HookResult struct {
+	Name     string
+	Duration time.Duration
+	Err      error
+	// TimedOut is set for hooks still running at the deadline and hooks never called because of it.
+	TimedOut bool
+}
+
+type Report struct {
+	Duration time.Duration
+	Hooks    []HookResult
+}
+
+// Err joins hook errors, nil when every hook stopped in time.
+func (r Report) Err() error {
+	var errs []error
+	for _, hook := range r.Hooks {
+		switch {
+		case hook.TimedOut:
+			errs = append(errs, fmt.Errorf("stop %s: %w", hook.Name, context.DeadlineExceeded))
+		case hook.Err != nil:
+			errs = append(errs, fmt.Errorf("stop %s: %w", hook.Name, hook.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Stop turns readiness off, waits PreStopDelay and runs OnStop hooks of started components
+// in reverse order, all within Timeout. Calling it again returns an empty report.
+func (m *Manager) Stop() Report {
+	if m.stopping.Swap(true) {
+		return Report{}
+	}
+	wasReady := m.ready.Swap(false)
+	start := time.Now()
+
+	ctx := context.Background()
+	if m.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.cfg.Timeout)
+		defer cancel()
+	}
+
+	// nobody routes traffic to an instance that never became ready
+	if wasReady && m.cfg.PreStopDelay > 0 {
+		m.log.Info("Waiting before shutdown", "delay", m.cfg.PreStopDelay)
+		select {
+		case <-time.After(m.cfg.PreStopDelay):
+		case <-ctx.Done():
+		}
+	}
+
+	m.mu.Lock()
+	started := m.started
+	m.started = nil
+	m.mu.Unlock()
+
+	var report Report
+	for i := len(started) - 1; i >= 0; i-- {
+		hook := started[i]
+		if hook.OnStop == nil {
+			continue
+		}
+		report.Hooks = append(report.Hooks, m.stopHook(ctx, hook))
+	}
+
+	report.Duration = time.Since(start)
+	m.logReport(report)
+	return report
+}
+
+func (m *Manager) stopHook(ctx context.Context, hook Hook) HookResult {
+	result := HookResult{Name: hook.Name}
+	if ctx.Err() != nil {
+		result.TimedOut = true
+		return result
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- hook.OnStop(ctx) }()
+
+	select {
+	case err := <-done:
+		result.Err = err
+	case <-ctx.Done():
+		result.TimedOut = true
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+func (m *Manager) logReport(report Report) {
+	for _, hook := range report.Hooks {
+		switch {
+		case hook.TimedOut:
+			m.log.Error("Component did not stop before the shutdown deadline", "component", hook.Name, "duration", hook.Duration)
+		case hook.Err != nil:
+			m.log.Error("Component failed to stop", "component", hook.Name, "duration", hook.Duration, "error", hook.Err)
+		default:
+			m.log.Debug("Component stopped", "component", hook.Name, "duration", hook.Duration)
+		}
+	}
+	m.log.Info("Shutdown finished", "duration", report.Duration, "failed", report.Err() != nil)
+}
@@ -0,0 +1,185 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"gotemplate/pkg/lifecycle"
+	"gotemplate/pkg/logs"
+)
+
+// recorder collects the order in which hooks run.
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) record(call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func (r *recorder) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	calls := r.calls
+	r.calls = nil
+	return calls
+}
+
+func (r *recorder) hook(name string, priority int) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name:     name,
+		Priority: priority,
+		OnStart:  func(context.Context) error { r.record("start " + name); return nil },
+		OnStop:   func(context.Context) error { r.record("stop " + name); return nil },
+	}
+}
+
+func TestStartAndStopOrder(t *testing.T) {
+	rec := &recorder{}
+	m := lifecycle.New(lifecycle.Config{}, logs.Default())
+	m.Append(rec.hook("http", lifecycle.PriorityServer))
+	m.Append(rec.hook("db", lifecycle.PriorityResource))
+	m.Append(rec.hook("admin", lifecycle.PriorityControl))
+	m.Append(rec.hook("cache", lifecycle.PriorityResource))
+	m.Append(rec.hook("consumer", lifecycle.PriorityServer))
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !m.Ready() {
+		t.Error("not ready after Start")
+	}
+	want := []string{"start admin", "start db", "start cache", "start http", "start consumer"}
+	if got := rec.take(); !slices.Equal(got, want) {
+		t.Errorf("start order %v, want %v", got, want)
+	}
+
+	report := m.Stop()
+	if err := report.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"stop consumer", "stop http", "stop cache", "stop db", "stop admin"}
+	if got := rec.take(); !slices.Equal(got, want) {
+		t.Errorf("stop order %v, want %v", got, want)
+	}
+	if m.Ready() || !m.Stopping() {
+		t.Errorf("ready %v, stopping %v after Stop", m.Ready(), m.Stopping())
+	}
+	if again := m.Stop(); len(again.Hooks) != 0 {
+		t.Errorf("second Stop ran %d hooks", len(again.Hooks))
+	}
+}
+
+func TestStartFailureRollsBack(t *testing.T) {
+	rec := &recorder{}
+	errBroken := errors.New("broken")
+	m := lifecycle.New(lifecycle.Config{PreStopDelay: time.Hour}, logs.Default())
+	m.Append(rec.hook("db", lifecycle.PriorityResource))
+	m.Append(rec.hook("cache", lifecycle.PriorityResource))
+	m.Append(lifecycle.Hook{
+		Name:     "http",
+		Priority: lifecycle.PriorityServer,
+		OnStart:  func(context.Context) error { return errBroken },
+		OnStop:   func(context.Context) error { rec.record("stop http"); return nil },
+	})
+	m.Append(rec.hook("consumer", lifecycle.PriorityServer))
+
+	// PreStopDelay is skipped: the app never became ready
+	done := make(chan error, 1)
+	go func() { done <- m.Start(context.Background()) }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, errBroken) {
+			t.Errorf("Start = %v, want the hook error", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("rollback waited for PreStopDelay")
+	}
+
+	want := []string{"start db", "start cache", "stop cache", "stop db"}
+	if got := rec.take(); !slices.Equal(got, want) {
+		t.Errorf("calls %v, want %v", got, want)
+	}
+	if m.Ready() {
+		t.Error("ready after a failed Start")
+	}
+}
+
+func TestPreStopDelay(t *testing.T) {
+	m := lifecycle.New(lifecycle.Config{PreStopDelay: 50 * time.Millisecond}, logs.Default())
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if report := m.Stop(); report.Duration < 50*time.Millisecond {
+		t.Errorf("Stop took %v, want at least the pre-stop delay", report.Duration)
+	}
+}
+
+func TestStopTimeout(t *testing.T) {
+	rec := &recorder{}
+	release := make(chan struct{})
+	defer close(release)
+
+	m := lifecycle.New(lifecycle.Config{Timeout: 50 * time.Millisecond}, logs.Default())
+	m.Append(rec.hook("db", lifecycle.PriorityResource))
+	m.Append(lifecycle.Hook{
+		Name:     "worker",
+		Priority: lifecycle.PriorityResource + 1,
+		// ignores ctx, like a component stuck in a call without a deadline
+		OnStop: func(context.Context) error { <-release; return nil },
+	})
+	m.Append(rec.hook("http", lifecycle.PriorityServer))
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	rec.take()
+
+	report := m.Stop()
+	got := make(map[string]bool)
+	for _, hook := range report.Hooks {
+		got[hook.Name] = hook.TimedOut
+	}
+	if want := map[string]bool{"http": false, "worker": true, "db": true}; !maps.Equal(got, want) {
+		t.Errorf("timed out %v, want %v", got, want)
+	}
+	if calls := rec.take(); !slices.Equal(calls, []string{"stop http"}) {
+		t.Errorf("calls %v, db must not be called after the deadline", calls)
+	}
+	if err := report.Err(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Err = %v, want a deadline error", err)
+	}
+}
+
+func TestFailKeepsFirstError(t *testing.T) {
+	m := lifecycle.New(lifecycle.Config{}, logs.Default())
+	errFirst := errors.New("first")
+
+	var wg sync.WaitGroup
+	m.Fail("http", errFirst)
+	for range 10 {
+		wg.Go(func() { m.Fail("consumer", errors.New("later")) })
+	}
+	wg.Wait()
+
+	select {
+	case err := <-m.Failed():
+		if !errors.Is(err, errFirst) || err.Error() != "http: first" {
+			t.Errorf("Failed delivered %v", err)
+		}
+	default:
+		t.Fatal("no failure delivered")
+	}
+	select {
+	case err := <-m.Failed():
+		t.Errorf("second failure delivered: %v", err)
+	default:
+	}
+}
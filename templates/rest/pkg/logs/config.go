@@ -0,0 +1,164 @@
+package logs
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	EncodingJSON    = "json"
+	EncodingConsole = "console"
+)
+
+type Config struct {
+	// Mode "debug" switches to development defaults: console encoding, colored levels, stack traces on warnings.
+	Mode  string
+	Level string
+	// Encoding is the default for sinks without their own, derived from Mode when empty.
+	Encoding string
+	// Sinks default to a single stdout sink.
+	Sinks    []SinkConfig
+	Sampling SamplingConfig
+	Async    AsyncConfig
+	Redact   RedactConfig
+}
+
+// SinkConfig describes one output. Output is "stdout", "stderr" or a file path;
+// Level is the minimum level written to this sink on top of the global level.
+type SinkConfig struct {
+	Name     string
+	Output   string
+	Level    string
+	Encoding string
+	Rotation RotationConfig
+}
+
+// SamplingConfig logs the first First entries with the same level and message every Tick,
+// then every Thereafter-th one. Zero First disables sampling.
+type SamplingConfig struct {
+	Tick       time.Duration
+	First      int
+	Thereafter int
+}
+
+// AsyncConfig buffers writes in memory; buffers are flushed every FlushInterval and on Sync.
+type AsyncConfig struct {
+	Enabled       bool
+	BufferSize    int
+	FlushInterval time.Duration
+}
+
+func newLogger(cfg Config) (*zap.Logger, error) {
+	if err := SetLevel("", cfg.Level, 0); err != nil {
+		return nil, fmt.Errorf("log level %q: %w", cfg.Level, err)
+	}
+	if err := SetRedaction(cfg.Redact); err != nil {
+		return nil, err
+	}
+
+	sinks := cfg.Sinks
+	if len(sinks) == 0 {
+		sinks = []SinkConfig{{Name: "stdout", Output: "stdout"}}
+	}
+
+	cores := make([]zapcore.Core, 0, len(sinks))
+	for _, sink := range sinks {
+		core, err := newSinkCore(cfg, sink)
+		if err != nil {
+			return nil, fmt.Errorf("log sink %q: %w", sink.Name, err)
+		}
+		cores = append(cores, core)
+	}
+
+	core := zapcore.NewTee(cores...)
+	if cfg.Sampling.First > 0 {
+		tick := cfg.Sampling.Tick
+		if tick <= 0 {
+			tick = time.Second
+		}
+		core = zapcore.NewSamplerWithOptions(core, tick, cfg.Sampling.First, cfg.Sampling.Thereafter)
+	}
+	core = levelCore{core}
+
+	options := []zap.Option{zap.AddCaller(), zap.AddCallerSkip(1), zap.ErrorOutput(zapcore.Lock(os.Stderr))}
+	if cfg.Mode == "debug" {
+		options = append(options, zap.Development(), zap.AddStacktrace(zapcore.WarnLevel))
+	} else {
+		options = append(options, zap.AddStacktrace(zapcore.ErrorLevel))
+	}
+	return zap.New(core, options...), nil
+}
+
+func newSinkCore(cfg Config, sink SinkConfig) (zapcore.Core, error) {
+	minLevel := zapcore.DebugLevel
+	if sink.Level != "" {
+		if err := minLevel.UnmarshalText([]byte(sink.Level)); err != nil {
+			return nil, err
+		}
+	}
+
+	var ws zapcore.WriteSyncer
+	terminal := true
+	switch sink.Output {
+	case "", "stdout":
+		ws = zapcore.Lock(os.Stdout)
+	case "stderr":
+		ws = zapcore.Lock(os.Stderr)
+	default:
+		file, err := newRotatingFile(sink.Output, sink.Rotation)
+		if err != nil {
+			return nil, err
+		}
+		ws = file
+		terminal = false
+	}
+
+	if cfg.Async.Enabled {
+		ws = &zapcore.BufferedWriteSyncer{WS: ws, Size: cfg.Async.BufferSize, FlushInterval: cfg.Async.FlushInterval}
+	}
+
+	encoder, err := newEncoder(cfg, sink, terminal)
+	if err != nil {
+		return nil, err
+	}
+
+	// the global and per logger levels are applied by levelCore around all sinks
+	return zapcore.NewCore(encoder, ws, minLevel), nil
+}
+
+func newEncoder(cfg Config, sink SinkConfig, terminal bool) (zapcore.Encoder, error) {
+	encoding := sink.Encoding
+	if encoding == "" {
+		encoding = cfg.Encoding
+	}
+
+	var encoderConfig zapcore.EncoderConfig
+	if cfg.Mode == "debug" {
+		encoderConfig = zap.NewDevelopmentEncoderConfig()
+		if encoding == "" {
+			encoding = EncodingConsole
+		}
+	} else {
+		encoderConfig = zap.NewProductionEncoderConfig()
+		if encoding == "" {
+			encoding = EncodingJSON
+		}
+	}
+
+	switch encoding {
+	case EncodingJSON:
+		return zapcore.NewJSONEncoder(encoderConfig), nil
+	case EncodingConsole:
+		// colors only make sense in a terminal, files get plain level names
+		if terminal && cfg.Mode == "debug" {
+			encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+		return zapcore.NewConsoleEncoder(encoderConfig), nil
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", encoding)
+	}
+}
@@ -0,0 +1,73 @@
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSinksSamplingAndAsync(t *testing.T) {
+	t.Cleanup(func() {
+		_ = SetLevel("", "info", 0)
+		_ = SetRedaction(RedactConfig{})
+	})
+
+	dir := t.TempDir()
+	errorsPath := filepath.Join(dir, "errors.log")
+	allPath := filepath.Join(dir, "all.log")
+	logger, err := newLogger(Config{
+		Level: "debug",
+		Sinks: []SinkConfig{
+			{Name: "errors", Output: errorsPath, Level: "warn"},
+			{Name: "all", Output: allPath, Encoding: EncodingConsole},
+		},
+		Sampling: SamplingConfig{Tick: time.Hour, First: 2},
+		Async:    AsyncConfig{Enabled: true, BufferSize: 64 * 1024, FlushInterval: time.Hour},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Debug("details")
+	for range 5 {
+		logger.Warn("disk almost full")
+	}
+
+	if data, _ := os.ReadFile(allPath); len(data) != 0 {
+		t.Errorf("async sink wrote before Sync: %q", data)
+	}
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path  string
+		lines int
+		// first is a part of the first line, it tells the encoding apart
+		first string
+	}{
+		{errorsPath, 2, `{"level":"warn"`},
+		{allPath, 3, "\tdebug\t"},
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		if len(lines) != tt.lines || !strings.Contains(lines[0], tt.first) {
+			t.Errorf("%s has %d line(s), want %d starting with %q:\n%s", filepath.Base(tt.path), len(lines), tt.lines, tt.first, data)
+		}
+	}
+}
+
+func TestUnknownEncodingFailsTheSink(t *testing.T) {
+	t.Cleanup(func() { _ = SetLevel("", "info", 0) })
+
+	_, err := newLogger(Config{Level: "info", Sinks: []SinkConfig{{Name: "stdout", Output: "stdout", Encoding: "xml"}}})
+	if err == nil || !strings.Contains(err.Error(), `log sink "stdout": unknown encoding "xml"`) {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+package logs
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelOverride is a level set for a named logger and everything below it:
+// "http" also covers "http.client". Until is zero for permanent changes.
+type LevelOverride struct {
+	Name  string    `json:"name,omitempty"`
+	Level string    `json:"level"`
+	Until time.Time `json:"until,omitzero"`
+}
+
+type LevelState struct {
+	Level     string          `json:"level"`
+	Until     time.Time       `json:"until,omitzero"`
+	Overrides []LevelOverride `json:"overrides,omitempty"`
+}
+
+var level = zap.NewAtomicLevel()
+
+// levels keeps runtime changes. Reads go through an immutable snapshot, so the
+// hot path of every log call is a single atomic load.
+var levels = &levelRegistry{reverts: make(map[string]*pendingRevert)}
+
+type levelRegistry struct {
+	mu        sync.Mutex
+	baseUntil time.Time
+	overrides map[string]LevelOverride
+	reverts   map[string]*pendingRevert
+	snapshot  atomic.Pointer[levelSnapshot]
+}
+
+type pendingRevert struct {
+	timer   *time.Timer
+	restore func()
+}
+
+type levelSnapshot struct {
+	min       zapcore.Level
+	overrides map[string]zapcore.Level
+}
+
+// SetLevel changes the level of the logger called name, the global level when name is empty.
+// A positive ttl restores the previous state when it expires. A change of the same logger
+// while a timed one is pending replaces it and drops its remaining time: a new ttl returns
+// to the state before the first change, a zero ttl keeps the new level.
+func SetLevel(name, text string, ttl time.Duration) error {
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(text)); err != nil {
+		return err
+	}
+	return levels.set(name, l, ttl)
+}
+
+// ResetLevel drops the override of a named logger.
+func ResetLevel(name string) {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	levels.stopRevert(name)
+	delete(levels.overrides, name)
+	levels.publish()
+}
+
+func Levels() LevelState {
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+
+	state := LevelState{Level: level.Level().String(), Until: levels.baseUntil}
+	for _, override := range levels.overrides {
+		state.Overrides = append(state.Overrides, override)
+	}
+	sort.Slice(state.Overrides, func(i, j int) bool { return state.Overrides[i].Name < state.Overrides[j].Name })
+	return state
+}
+
+func (r *levelRegistry) set(name string, l zapcore.Level, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// extending a timed change must still return to the state before the first one
+	restore := r.restoreFunc(name)
+	if pending, ok := r.reverts[name]; ok {
+		restore = pending.restore
+	}
+	r.stopRevert(name)
+
+	var until time.Time
+	if ttl > 0 {
+		until = time.Now().Add(ttl)
+		pending := &pendingRevert{restore: restore}
+		pending.timer = time.AfterFunc(ttl, func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if r.reverts[name] != pending {
+				return
+			}
+			delete(r.reverts, name)
+			pending.restore()
+			r.publish()
+		})
+		r.reverts[name] = pending
+	}
+
+	if name == "" {
+		level.SetLevel(l)
+		r.baseUntil = until
+	} else {
+		if r.overrides == nil {
+			r.overrides = make(map[string]LevelOverride)
+		}
+		r.overrides[name] = LevelOverride{Name: name, Level: l.String(), Until: until}
+	}
+	r.publish()
+	return nil
+}
+
+// restoreFunc captures the current state of name, the caller holds the lock.
+func (r *levelRegistry) restoreFunc(name string) func() {
+	if name == "" {
+		previous := level.Level()
+		return func() {
+			level.SetLevel(previous)
+			r.baseUntil = time.Time{}
+		}
+	}
+
+	previous, ok := r.overrides[name]
+	return func() {
+		if ok {
+			previous.Until = time.Time{}
+			r.overrides[name] = previous
+		} else {
+			delete(r.overrides, name)
+		}
+	}
+}
+
+func (r *levelRegistry) stopRevert(name string) {
+	if pending, ok := r.reverts[name]; ok {
+		pending.timer.Stop()
+		delete(r.reverts, name)
+	}
+}
+
+// publish stores a snapshot of the current levels, the caller holds the lock.
+func (r *levelRegistry) publish() {
+	snapshot := &levelSnapshot{min: level.Level(), overrides: make(map[string]zapcore.Level, len(r.overrides))}
+	for name, override := range r.overrides {
+		var l zapcore.Level
+		_ = l.UnmarshalText([]byte(override.Level))
+		snapshot.overrides[name] = l
+		if l < snapshot.min {
+			snapshot.min = l
+		}
+	}
+	r.snapshot.Store(snapshot)
+}
+
+// enabled is the cheap check done before an entry is built: could anything log at l.
+func (r *levelRegistry) enabled(l zapcore.Level) bool {
+	snapshot := r.snapshot.Load()
+	if snapshot == nil {
+		return level.Enabled(l)
+	}
+	return l >= snapshot.min
+}
+
+// levelFor picks the override with the longest matching name prefix.
+func (r *levelRegistry) levelFor(name string) zapcore.Level {
+	snapshot := r.snapshot.Load()
+	if snapshot == nil || len(snapshot.overrides) == 0 || name == "" {
+		return level.Level()
+	}
+
+	for candidate := name; ; {
+		if l, ok := snapshot.overrides[candidate]; ok {
+			return l
+		}
+		i := strings.LastIndexByte(candidate, '.')
+		if i < 0 {
+			return level.Level()
+		}
+		candidate = candidate[:i]
+	}
+}
+
+// levelCore applies runtime levels, including per logger name overrides, on top of the sinks.
+type levelCore struct {
+	zapcore.Core
+}
+
+func (c levelCore) Enabled(l zapcore.Level) bool {
+	return levels.enabled(l) && c.Core.Enabled(l)
+}
+
+func (c levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return levelCore{c.Core.With(fields)}
+}
+
+func (c levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level < levels.levelFor(entry.LoggerName) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
@@ -0,0 +1,56 @@
+package logs
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRuntimeLevels(t *testing.T) {
+	t.Cleanup(func() {
+		ResetLevel("http")
+		_ = SetLevel("", "info", 0)
+	})
+	if err := SetLevel("", "info", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	core, recorded := observer.New(zapcore.DebugLevel)
+	logger := New(zap.New(levelCore{core}))
+
+	if err := SetLevel("http", "debug", 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	logger.Debug("root")
+	logger.Named("http").Named("client").Debug("nested")
+	logger.Named("https").Debug("sibling")
+
+	if got := recorded.TakeAll(); len(got) != 1 || got[0].Message != "nested" {
+		t.Fatalf("expected only the http.client entry, got %v", got)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	logger.Named("http").Debug("after revert")
+	if recorded.Len() != 0 {
+		t.Errorf("override was not reverted: %v", Levels())
+	}
+	if len(Levels().Overrides) != 0 {
+		t.Errorf("expired override is still listed: %v", Levels())
+	}
+}
+
+func TestTimedLevelRestoresOriginal(t *testing.T) {
+	t.Cleanup(func() { _ = SetLevel("", "info", 0) })
+	_ = SetLevel("", "info", 0)
+
+	_ = SetLevel("", "debug", time.Hour)
+	_ = SetLevel("", "warn", 50*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+
+	if got := Levels().Level; got != "info" {
+		t.Errorf("level = %s, want info restored after the extended change", got)
+	}
+}
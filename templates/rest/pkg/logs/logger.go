@@ -0,0 +1,95 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// Logger is the injectable counterpart of the package functions, with the same field
+// conventions: key/value pairs, zap.Field or slog.Attr.
+type Logger struct {
+	zap *zap.Logger
+}
+
+// New wraps a zap logger. Package functions and Logger methods add one frame,
+// so z is expected to be built with zap.AddCallerSkip(1).
+func New(z *zap.Logger) *Logger {
+	return &Logger{zap: z}
+}
+
+func NewNop() *Logger {
+	return New(zap.NewNop())
+}
+
+var defaultLogger atomic.Pointer[Logger]
+
+func init() {
+	defaultLogger.Store(NewNop())
+}
+
+// Default returns the logger behind the package functions, a no-op one until Init.
+func Default() *Logger {
+	return defaultLogger.Load()
+}
+
+// SetDefault replaces the logger behind the package functions and slog.Default.
+func SetDefault(l *Logger) {
+	defaultLogger.Store(l)
+	slog.SetDefault(l.Slog())
+}
+
+func (l *Logger) Debug(msg string, fields ...any) { write(l.zap.Check(zap.DebugLevel, msg), fields) }
+func (l *Logger) Info(msg string, fields ...any)  { write(l.zap.Check(zap.InfoLevel, msg), fields) }
+func (l *Logger) Warn(msg string, fields ...any)  { write(l.zap.Check(zap.WarnLevel, msg), fields) }
+func (l *Logger) Error(msg string, fields ...any) { write(l.zap.Check(zap.ErrorLevel, msg), fields) }
+func (l *Logger) Panic(msg string, fields ...any) { write(l.zap.Check(zap.PanicLevel, msg), fields) }
+func (l *Logger) Fatal(msg string, fields ...any) { write(l.zap.Check(zap.FatalLevel, msg), fields) }
+
+func (l *Logger) DebugCtx(ctx context.Context, msg string, fields ...any) {
+	write(l.fromContext(ctx).Check(zap.DebugLevel, msg), fields)
+}
+
+func (l *Logger) InfoCtx(ctx context.Context, msg string, fields ...any) {
+	write(l.fromContext(ctx).Check(zap.InfoLevel, msg), fields)
+}
+
+func (l *Logger) WarnCtx(ctx context.Context, msg string, fields ...any) {
+	write(l.fromContext(ctx).Check(zap.WarnLevel, msg), fields)
+}
+
+func (l *Logger) ErrorCtx(ctx context.Context, msg string, fields ...any) {
+	write(l.fromContext(ctx).Check(zap.ErrorLevel, msg), fields)
+}
+
+func (l *Logger) fromContext(ctx context.Context) *zap.Logger {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		return l.zap.With(zap.String("request_id", requestID))
+	}
+	return l.zap
+}
+
+func (l *Logger) With(fields ...any) *Logger {
+	return New(l.zap.With(toZapFields(fields)...))
+}
+
+// Named adds a dotted segment to the logger name, e.g. "http" or "clients.billing".
+func (l *Logger) Named(name string) *Logger {
+	return New(l.zap.Named(name))
+}
+
+// Zap exposes the underlying logger for libraries that take *zap.Logger.
+func (l *Logger) Zap() *zap.Logger {
+	return l.zap.WithOptions(zap.AddCallerSkip(-1))
+}
+
+// Slog returns a slog.Logger writing to the same cores.
+func (l *Logger) Slog() *slog.Logger {
+	return slog.New(NewSlogHandler(l.zap))
+}
+
+func (l *Logger) Sync() error {
+	return l.zap.Sync()
+}
@@ -0,0 +1,52 @@
+package logs_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"gotemplate/pkg/logs"
+	"gotemplate/pkg/logs/logstest"
+)
+
+func TestDefaultIsNoop(t *testing.T) {
+	logs.Info("logged before Init", "key", "value")
+	logs.Default().Named("test").Error("still no panic")
+}
+
+func TestFieldKinds(t *testing.T) {
+	recorded := logstest.Capture(t)
+
+	logs.Info("message", zap.Int("zap", 1), slog.Int("slog", 2), 3, "non-string key", "dangling")
+
+	fields := recorded.All()[0].ContextMap()
+	for key, expected := range map[string]any{"zap": int64(1), "slog": int64(2), "3": "non-string key", "!BADKEY": "dangling"} {
+		if fields[key] != expected {
+			t.Errorf("field %q = %v, want %v (all: %v)", key, fields[key], expected, fields)
+		}
+	}
+}
+
+func TestSlogSharesOutput(t *testing.T) {
+	recorded := logstest.Capture(t)
+
+	ctx := logs.ContextWithRequestID(context.Background(), "req-1")
+	slog.WarnContext(ctx, "from slog", "password", "secret", slog.Group("user", "id", 7))
+
+	entries := recorded.FilterMessage("from slog").All()
+	if len(entries) != 1 {
+		t.Fatalf("expected one entry, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["request_id"] != "req-1" || fields["password"] != logs.Redacted {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+	if user, _ := fields["user"].(map[string]any); user["id"] != int64(7) {
+		t.Errorf("group was not nested: %v", fields)
+	}
+	if entries[0].Level != zap.WarnLevel {
+		t.Errorf("level = %s, want warn", entries[0].Level)
+	}
+}
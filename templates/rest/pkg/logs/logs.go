@@ -3,6 +3,8 @@ package logs
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -10,65 +12,46 @@ import (
 	customErrors "gotemplate/pkg/errors"
 )
 
-var packageLogger *zap.Logger
-
-func Init(mode, logLevel string) {
-	var zapConfig zap.Config
-
-	if mode == "debug" {
-		zapConfig = zap.NewDevelopmentConfig()
-		zapConfig.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
-	} else {
-		zapConfig = zap.NewProductionConfig()
-	}
-	var err error
-
-	zapConfig.Level, err = zap.ParseAtomicLevel(logLevel)
-	if err != nil {
-		panic(err)
-	}
-
-	zapConfig.OutputPaths = []string{"stdout"}
-	zapConfig.ErrorOutputPaths = []string{"stderr"}
-
-	packageLogger, err = zapConfig.Build(zap.AddCaller(), zap.AddCallerSkip(1))
+// Init builds the package logger from cfg and applies its redaction rules.
+func Init(cfg Config) {
+	logger, err := newLogger(cfg)
 	if err != nil {
 		panic(err)
 	}
+	SetDefault(New(logger))
 }
 
 func Info(msg string, fields ...any) {
-	packageLogger.Info(msg, toZapFields(fields)...)
+	write(Default().zap.Check(zap.InfoLevel, msg), fields)
 }
 
 func Warn(msg string, fields ...any) {
-	packageLogger.Warn(msg, toZapFields(fields)...)
+	write(Default().zap.Check(zap.WarnLevel, msg), fields)
 }
 
 func Error(msg string, fields ...any) {
-	packageLogger.Error(msg, toZapFields(fields)...)
+	write(Default().zap.Check(zap.ErrorLevel, msg), fields)
 }
 
 func Fatal(msg string, fields ...any) {
-	packageLogger.Fatal(msg, toZapFields(fields)...)
+	write(Default().zap.Check(zap.FatalLevel, msg), fields)
 }
 
 func Panic(msg string, fields ...any) {
-	packageLogger.Panic(msg, toZapFields(fields)...)
+	write(Default().zap.Check(zap.PanicLevel, msg), fields)
 }
 
 func Debug(msg string, fields ...any) {
-	packageLogger.Debug(msg, toZapFields(fields)...)
+	write(Default().zap.Check(zap.DebugLevel, msg), fields)
 }
 
+// Sync flushes buffered sinks, call it before the process exits.
 func Sync() {
-	if packageLogger != nil {
-		_ = packageLogger.Sync()
-	}
+	_ = Default().Sync()
 }
 
 func With(fields ...any) *zap.Logger {
-	return packageLogger.With(toZapFields(fields)...)
+	return Default().zap.With(toZapFields(fields)...)
 }
 
 type contextKey string
@@ -79,65 +62,126 @@ func ContextWithRequestID(ctx context.Context, requestID string) context.Context
 	return context.WithValue(ctx, requestIDKey, requestID)
 }
 
-func FromContext(ctx context.Context) *zap.Logger {
+func RequestIDFromContext(ctx context.Context) string {
 	if ctx == nil {
-		return packageLogger
-	}
-
-	requestID, ok := ctx.Value(requestIDKey).(string)
-	if !ok || requestID == "" {
-		return packageLogger
+		return ""
 	}
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
 
-	return packageLogger.With(zap.String("request_id", requestID))
+func FromContext(ctx context.Context) *zap.Logger {
+	return Default().fromContext(ctx)
 }
 
 func InfoCtx(ctx context.Context, msg string, fields ...any) {
-	FromContext(ctx).Info(msg, toZapFields(fields)...)
+	write(FromContext(ctx).Check(zap.InfoLevel, msg), fields)
 }
 
 func ErrorCtx(ctx context.Context, msg string, fields ...any) {
-	FromContext(ctx).Error(msg, toZapFields(fields)...)
+	write(FromContext(ctx).Check(zap.ErrorLevel, msg), fields)
 }
 
 func WarnCtx(ctx context.Context, msg string, fields ...any) {
-	FromContext(ctx).Warn(msg, toZapFields(fields)...)
+	write(FromContext(ctx).Check(zap.WarnLevel, msg), fields)
 }
 
 func DebugCtx(ctx context.Context, msg string, fields ...any) {
-	FromContext(ctx).Debug(msg, toZapFields(fields)...)
+	write(FromContext(ctx).Check(zap.DebugLevel, msg), fields)
 }
 
 func PanicCtx(ctx context.Context, msg string, fields ...any) {
-	FromContext(ctx).Panic(msg, toZapFields(fields)...)
+	write(FromContext(ctx).Check(zap.PanicLevel, msg), fields)
 }
 
 func FatalCtx(ctx context.Context, msg string, fields ...any) {
-	FromContext(ctx).Fatal(msg, toZapFields(fields)...)
+	write(FromContext(ctx).Check(zap.FatalLevel, msg), fields)
 }
 
+// LogCtx logs err with the fields attached via customErrors.WithField and its stack, if captured.
+// Errors caused by clients or upstreams are logged as warnings.
 func LogCtx(ctx context.Context, err error, msg string, fields ...any) {
-	fields = append(fields, "error", err)
-	if errors.Is(err, customErrors.ErrExternalService) || errors.Is(err, customErrors.ErrDataNotFound) {
+	fields = append(fields, "error", err, "error_code", customErrors.Code(err))
+	for key, value := range customErrors.Fields(err) {
+		fields = append(fields, key, value)
+	}
+	if stack := customErrors.StackTrace(err); stack != "" {
+		fields = append(fields, "stacktrace", stack)
+	}
+
+	if isWarning(err) {
 		WarnCtx(ctx, msg, fields...)
 	} else {
 		ErrorCtx(ctx, msg, fields...)
 	}
 }
 
+func isWarning(err error) bool {
+	for _, kind := range []error{
+		customErrors.ErrExternalService,
+		customErrors.ErrDataNotFound,
+		customErrors.ErrConflict,
+		customErrors.ErrUnauthorized,
+		customErrors.ErrRateLimited,
+		customErrors.ErrTooLarge,
+	} {
+		if errors.Is(err, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// write builds and redacts the fields only for entries that passed the level check.
+// Callers pass the result of Check directly, so the caller frame stays the same as for zap.Info.
+func write(entry *zapcore.CheckedEntry, fields []any) {
+	if entry != nil {
+		entry.Write(toZapFields(fields)...)
+	}
+}
+
+// toZapFields accepts key/value pairs as well as ready zap.Field and slog.Attr values.
+// Keys that are not strings are formatted, a trailing value without a key goes under "!BADKEY".
 func toZapFields(fields []any) []zap.Field {
 	if len(fields) == 0 {
 		return nil
 	}
+	r := redactor.Load()
 	zapFields := make([]zap.Field, 0, len(fields)/2)
-	for i := 0; i < len(fields); i += 2 {
-		if i+1 < len(fields) {
-			key, ok := fields[i].(string)
-			if !ok {
-				continue
-			}
-			zapFields = append(zapFields, zap.Any(key, fields[i+1]))
+	for i := 0; i < len(fields); i++ {
+		switch field := fields[i].(type) {
+		case zap.Field:
+			zapFields = append(zapFields, redactField(r, field))
+			continue
+		case slog.Attr:
+			zapFields = appendAttr(zapFields, field)
+			continue
+		}
+
+		if i+1 == len(fields) {
+			zapFields = append(zapFields, zap.Any(badKey, r.Value(badKey, fields[i])))
+			break
+		}
+		key, ok := fields[i].(string)
+		if !ok {
+			key = fmt.Sprint(fields[i])
 		}
+		zapFields = append(zapFields, zap.Any(key, r.Value(key, fields[i+1])))
+		i++
 	}
 	return zapFields
 }
+
+const badKey = "!BADKEY"
+
+func redactField(r *Redactor, field zap.Field) zap.Field {
+	switch {
+	case r.IsSensitiveKey(field.Key):
+		return zap.String(field.Key, Redacted)
+	case field.Type == zapcore.StringType:
+		field.String = r.String(field.String)
+	case field.Type == zapcore.ErrorType, field.Type == zapcore.ReflectType, field.Type == zapcore.ByteStringType:
+		return zap.Any(field.Key, r.Value(field.Key, field.Interface))
+	}
+	return field
+}
@@ -0,0 +1,29 @@
+// Package logstest provides loggers that record entries for assertions in tests.
+package logstest
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"gotemplate/pkg/logs"
+)
+
+// New returns a logger recording every entry, redacted the same way as in production.
+func New() (*logs.Logger, *observer.ObservedLogs) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	return logs.New(zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))), recorded
+}
+
+// Capture makes a recording logger the package default until the test ends.
+func Capture(tb testing.TB) *observer.ObservedLogs {
+	tb.Helper()
+
+	logger, recorded := New()
+	previous := logs.Default()
+	logs.SetDefault(logger)
+	tb.Cleanup(func() { logs.SetDefault(previous) })
+	return recorded
+}
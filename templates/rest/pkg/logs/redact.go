@@ -0,0 +1,379 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+const Redacted = "[REDACTED]"
+
+// RedactConfig extends the default redaction rules. Keys and headers are matched
+// case-insensitively ignoring '-' and '_', so "access_token" also covers "accessToken".
+// JSON paths are dotted, "*" matches any key or array index: "cards.*.number".
+type RedactConfig struct {
+	Keys      []string
+	Headers   []string
+	JSONPaths []string
+	Patterns  []string
+}
+
+var DefaultRedactConfig = RedactConfig{
+	Keys: []string{
+		"password", "passwd", "secret", "client_secret", "token", "access_token", "refresh_token",
+		"id_token", "api_key", "apikey", "authorization", "cookie", "set_cookie", "private_key",
+		"card_number", "pan", "cvv", "cvc", "pin",
+	},
+	Headers: []string{
+		"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Auth-Token",
+	},
+}
+
+var (
+	cardPattern  = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	jwtPattern   = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+)
+
+type Redactor struct {
+	keys     map[string]bool
+	headers  map[string]bool
+	paths    [][]string
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor builds a redactor from the defaults plus cfg.
+func NewRedactor(cfg RedactConfig) (*Redactor, error) {
+	r := &Redactor{
+		keys:     make(map[string]bool),
+		headers:  make(map[string]bool),
+		patterns: []*regexp.Regexp{emailPattern, jwtPattern},
+	}
+	for _, key := range append(DefaultRedactConfig.Keys, cfg.Keys...) {
+		r.keys[normalizeKey(key)] = true
+	}
+	for _, header := range append(DefaultRedactConfig.Headers, cfg.Headers...) {
+		r.headers[normalizeKey(header)] = true
+	}
+	for _, path := range append(DefaultRedactConfig.JSONPaths, cfg.JSONPaths...) {
+		r.paths = append(r.paths, strings.Split(path, "."))
+	}
+	for _, pattern := range append(DefaultRedactConfig.Patterns, cfg.Patterns...) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redact pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+var redactor atomic.Pointer[Redactor]
+
+func init() {
+	r, _ := NewRedactor(RedactConfig{})
+	redactor.Store(r)
+}
+
+// SetRedaction replaces the redaction rules used by every logging call.
+func SetRedaction(cfg RedactConfig) error {
+	r, err := NewRedactor(cfg)
+	if err != nil {
+		return err
+	}
+	redactor.Store(r)
+	return nil
+}
+
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(key))
+}
+
+func (r *Redactor) IsSensitiveKey(key string) bool {
+	return r.keys[normalizeKey(key)]
+}
+
+func (r *Redactor) IsSensitiveHeader(name string) bool {
+	return r.headers[normalizeKey(name)] || r.keys[normalizeKey(name)]
+}
+
+// String masks pattern matches. JSON documents are redacted structurally.
+func (r *Redactor) String(s string) string {
+	if trimmed := strings.TrimSpace(s); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid([]byte(trimmed)) {
+		return r.JSON([]byte(trimmed))
+	}
+
+	s = cardPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if luhnValid(match) {
+			return Redacted
+		}
+		return match
+	})
+	for _, re := range r.patterns {
+		s = re.ReplaceAllString(s, Redacted)
+	}
+	return s
+}
+
+// JSON redacts sensitive keys, configured paths and patterns inside a JSON document.
+// Non-JSON input is treated as plain text.
+func (r *Redactor) JSON(body []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return r.String(string(body))
+	}
+	doc = r.walk(doc, nil)
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return Redacted
+	}
+	return string(out)
+}
+
+func (r *Redactor) walk(node any, path []string) any {
+	if r.matchesPath(path) {
+		return Redacted
+	}
+
+	switch v := node.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if r.IsSensitiveKey(key) {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = r.walk(value, append(path, key))
+		}
+		return v
+	case []any:
+		for i, value := range v {
+			v[i] = r.walk(value, append(path, fmt.Sprint(i)))
+		}
+		return v
+	case string:
+		return r.String(v)
+	case json.Number:
+		if luhnValid(v.String()) && len(v.String()) >= 13 {
+			return Redacted
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func (r *Redactor) matchesPath(path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	for _, pattern := range r.paths {
+		if len(pattern) != len(path) {
+			continue
+		}
+		matched := true
+		for i := range pattern {
+			if pattern[i] != "*" && !strings.EqualFold(pattern[i], path[i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redactor) Header(name, value string) string {
+	if r.IsSensitiveHeader(name) {
+		return Redacted
+	}
+	return r.String(value)
+}
+
+// Query redacts values of sensitive parameters in a raw query string.
+func (r *Redactor) Query(rawQuery string) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return r.String(rawQuery)
+	}
+	for key, list := range values {
+		for i := range list {
+			if r.IsSensitiveKey(key) {
+				list[i] = Redacted
+			} else {
+				list[i] = r.String(list[i])
+			}
+		}
+	}
+	return values.Encode()
+}
+
+// URL redacts the query part and user info of rawURL.
+func (r *Redactor) URL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return r.String(rawURL)
+	}
+	if u.User != nil {
+		u.User = url.User(Redacted)
+	}
+	u.RawQuery = r.Query(u.RawQuery)
+	return u.String()
+}
+
+// Value redacts a log field value: the whole value when key is sensitive, otherwise
+// strings, errors, byte slices and string-keyed maps are scanned recursively.
+func (r *Redactor) Value(key string, value any) any {
+	if r.IsSensitiveKey(key) {
+		return Redacted
+	}
+
+	switch v := value.(type) {
+	case nil, Lazy:
+		return value
+	case string:
+		return r.String(v)
+	case []byte:
+		return r.JSON(v)
+	case error:
+		// keep the error itself, so zap still renders it as an error, unless something was masked
+		if msg := v.Error(); r.String(msg) != msg {
+			return r.String(msg)
+		}
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return value
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return value
+		}
+		result := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			result[k] = r.Value(k, iter.Value().Interface())
+		}
+		return result
+	case reflect.Slice, reflect.Array, reflect.Struct:
+		return r.structured(value)
+	case reflect.String:
+		return r.String(rv.String())
+	default:
+		return value
+	}
+}
+
+// structured redacts slices and structs through their JSON form, which respects json tags
+// and catches key/value pairs such as httpclient.Header. Values without anything to mask
+// are returned as is, so time.Time and friends keep their encoding.
+func (r *Redactor) structured(value any) any {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var doc any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return value
+	}
+	if list, ok := doc.([]any); ok {
+		for _, item := range list {
+			redactPair(r, item)
+		}
+	}
+	doc = r.walk(doc, nil)
+
+	redacted, err := json.Marshal(doc)
+	if err != nil {
+		return Redacted
+	}
+	if bytes.Equal(redacted, data) {
+		return value
+	}
+	return json.RawMessage(redacted)
+}
+
+// redactPair handles {"Key": "Authorization", "Value": "..."} shaped items.
+func redactPair(r *Redactor, item any) {
+	m, ok := item.(map[string]any)
+	if !ok {
+		return
+	}
+	for _, keyField := range []string{"Key", "key", "Name", "name"} {
+		name, ok := m[keyField].(string)
+		if !ok || !r.IsSensitiveHeader(name) {
+			continue
+		}
+		for _, valueField := range []string{"Value", "value"} {
+			if _, ok := m[valueField]; ok {
+				m[valueField] = Redacted
+			}
+		}
+	}
+}
+
+func luhnValid(s string) bool {
+	sum, digits := 0, 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
+
+// Lazy is a log value computed by the encoder, so it costs nothing when the level is
+// disabled. Its String method does the work.
+type Lazy func() string
+
+func (f Lazy) String() string { return f() }
+
+// RedactJSON defers Redactor.JSON to the encoder: pass it as a log value, call String for text.
+func RedactJSON(body []byte) Lazy {
+	return func() string { return redactor.Load().JSON(body) }
+}
+
+func RedactString(s string) string           { return redactor.Load().String(s) }
+func RedactHeader(name, value string) string { return redactor.Load().Header(name, value) }
+func RedactQuery(rawQuery string) string     { return redactor.Load().Query(rawQuery) }
+func RedactURL(rawURL string) string         { return redactor.Load().URL(rawURL) }
+func RedactValue(key string, value any) any  { return redactor.Load().Value(key, value) }
@@ -0,0 +1,174 @@
+package logs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotationConfig controls file sinks. Zero values disable the corresponding limit.
+type RotationConfig struct {
+	MaxSizeMB  int
+	Interval   time.Duration
+	MaxAge     time.Duration
+	MaxBackups int
+}
+
+// rotateRetry is how long a failed rotation waits before it is tried again.
+const rotateRetry = time.Minute
+
+// rotatingFile is a WriteSyncer that rotates by size and/or age and prunes old backups.
+// Backups are named app-2006-01-02T15-04-05.000.log next to app.log.
+type rotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	cfg      RotationConfig
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	// retryAt holds rotation back after a failure, so the error is reported once
+	retryAt time.Time
+	now     func() time.Time
+}
+
+func newRotatingFile(path string, cfg RotationConfig) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	r := &rotatingFile{path: path, cfg: cfg, now: time.Now}
+	file, info, err := r.open()
+	if err != nil {
+		return nil, err
+	}
+	r.file, r.size, r.openedAt = file, info.Size(), r.now()
+	// a restart continues the interval of the existing file: it was started by the last
+	// rotation, or at the latest when it was last written
+	if info.Size() > 0 {
+		r.openedAt = info.ModTime()
+		if backups := r.backups(); len(backups) > 0 {
+			r.openedAt = backups[0].at
+		}
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() (*os.File, os.FileInfo, error) {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+	return file, info, nil
+}
+
+// Write reports a failed rotation but still writes p to the current file.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var rotateErr error
+	if r.shouldRotate(len(p)) {
+		if rotateErr = r.rotate(); rotateErr != nil {
+			r.retryAt = r.now().Add(rotateRetry)
+			rotateErr = fmt.Errorf("rotate %s: %w", r.path, rotateErr)
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	if err != nil {
+		return n, err
+	}
+	return n, rotateErr
+}
+
+func (r *rotatingFile) shouldRotate(next int) bool {
+	if r.size == 0 || r.now().Before(r.retryAt) {
+		return false
+	}
+	if r.cfg.MaxSizeMB > 0 && r.size+int64(next) > int64(r.cfg.MaxSizeMB)*1024*1024 {
+		return true
+	}
+	return r.cfg.Interval > 0 && r.now().Sub(r.openedAt) >= r.cfg.Interval
+}
+
+// rotate keeps the old file open until the new one is, so a failure leaves logging
+// where it was: in path, or in the backup it was renamed to.
+func (r *rotatingFile) rotate() error {
+	now := r.now()
+	ext := filepath.Ext(r.path)
+	backup := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(r.path, ext), now.Format(backupTimeFormat), ext)
+	if err := os.Rename(r.path, backup); err != nil {
+		return err
+	}
+	file, _, err := r.open()
+	if err != nil {
+		return err
+	}
+
+	_ = r.file.Close()
+	r.file, r.size, r.openedAt = file, 0, now
+	r.prune()
+	return nil
+}
+
+type backupFile struct {
+	path string
+	at   time.Time
+}
+
+// backups lists the backups of path, newest first.
+func (r *rotatingFile) backups() []backupFile {
+	ext := filepath.Ext(r.path)
+	prefix := filepath.Base(strings.TrimSuffix(r.path, ext)) + "-"
+	entries, err := os.ReadDir(filepath.Dir(r.path))
+	if err != nil {
+		return nil
+	}
+
+	var backups []backupFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		at, err := time.ParseInLocation(backupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext), time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{filepath.Join(filepath.Dir(r.path), name), at})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].at.After(backups[j].at) })
+	return backups
+}
+
+// prune removes backups beyond MaxBackups or older than MaxAge. Failures are ignored:
+// a leftover file is better than a lost log line.
+func (r *rotatingFile) prune() {
+	if r.cfg.MaxBackups <= 0 && r.cfg.MaxAge <= 0 {
+		return
+	}
+	for i, b := range r.backups() {
+		expired := r.cfg.MaxAge > 0 && r.now().Sub(b.at) > r.cfg.MaxAge
+		excess := r.cfg.MaxBackups > 0 && i >= r.cfg.MaxBackups
+		if expired || excess {
+			_ = os.Remove(b.path)
+		}
+	}
+}
+
+func (r *rotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Sync()
+}
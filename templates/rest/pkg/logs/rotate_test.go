@@ -0,0 +1,180 @@
+package logs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixedClock returns a now func for rotatingFile that the test moves by hand.
+func fixedClock(start time.Time) (func() time.Time, func(time.Duration)) {
+	now := start
+	return func() time.Time { return now }, func(d time.Duration) { now = now.Add(d) }
+}
+
+func openRotating(t *testing.T, cfg RotationConfig, now func() time.Time) (*rotatingFile, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.log")
+	r, err := newRotatingFile(path, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.now, r.openedAt = now, now()
+	t.Cleanup(func() { _ = r.file.Close() })
+	return r, path
+}
+
+func backupNames(t *testing.T, r *rotatingFile) []string {
+	t.Helper()
+	var names []string
+	for _, b := range r.backups() {
+		names = append(names, filepath.Base(b.path))
+	}
+	return names
+}
+
+func TestRotateBySize(t *testing.T) {
+	now, advance := fixedClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local))
+	r, path := openRotating(t, RotationConfig{MaxSizeMB: 1}, now)
+
+	chunk := bytes.Repeat([]byte("x"), 600*1024)
+	for range 3 {
+		if _, err := r.Write(chunk); err != nil {
+			t.Fatal(err)
+		}
+		advance(time.Second)
+	}
+
+	want := []string{"app-2024-05-01T10-00-02.000.log", "app-2024-05-01T10-00-01.000.log"}
+	if got := backupNames(t, r); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("backups %v, want %v", got, want)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != int64(len(chunk)) {
+		t.Errorf("current file %v, %v", info, err)
+	}
+}
+
+func TestRotateByInterval(t *testing.T) {
+	now, advance := fixedClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local))
+	r, _ := openRotating(t, RotationConfig{Interval: time.Hour}, now)
+
+	_, _ = r.Write([]byte("first\n"))
+	advance(59 * time.Minute)
+	_, _ = r.Write([]byte("second\n"))
+	if got := backupNames(t, r); len(got) != 0 {
+		t.Fatalf("rotated before the interval: %v", got)
+	}
+	advance(time.Minute)
+	_, _ = r.Write([]byte("third\n"))
+	if got := backupNames(t, r); len(got) != 1 {
+		t.Errorf("backups %v, want one after the interval", got)
+	}
+}
+
+func TestRotateIntervalSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	started := time.Now().Add(-2 * time.Hour).Truncate(time.Millisecond)
+	last := filepath.Join(dir, "app-"+started.Format(backupTimeFormat)+".log")
+	for _, name := range []string{path, last} {
+		if err := os.WriteFile(name, []byte("line\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := newRotatingFile(path, RotationConfig{Interval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.file.Close() }()
+	if !r.openedAt.Equal(started) {
+		t.Errorf("openedAt %v, want the time of the last rotation %v", r.openedAt, started)
+	}
+	if !r.shouldRotate(1) {
+		t.Error("a restart must not restart the interval")
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	start := time.Date(2024, 5, 10, 10, 0, 0, 0, time.Local)
+	tests := []struct {
+		name string
+		cfg  RotationConfig
+		want []string
+	}{
+		{"max backups", RotationConfig{MaxSizeMB: 1, MaxBackups: 2}, []string{
+			"app-2024-05-10T10-00-00.000.log", "app-2024-05-09T10-00-00.000.log",
+		}},
+		{"max age", RotationConfig{MaxSizeMB: 1, MaxAge: 36 * time.Hour}, []string{
+			"app-2024-05-10T10-00-00.000.log", "app-2024-05-09T10-00-00.000.log",
+		}},
+		{"unlimited", RotationConfig{MaxSizeMB: 1}, []string{
+			"app-2024-05-10T10-00-00.000.log", "app-2024-05-09T10-00-00.000.log",
+			"app-2024-05-08T10-00-00.000.log", "app-2024-05-07T10-00-00.000.log",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now, _ := fixedClock(start)
+			r, path := openRotating(t, tt.cfg, now)
+			for days := 1; days <= 3; days++ {
+				old := filepath.Join(filepath.Dir(path), "app-"+start.AddDate(0, 0, -days).Format(backupTimeFormat)+".log")
+				if err := os.WriteFile(old, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// not a backup of app.log, pruning must leave it alone
+			other := filepath.Join(filepath.Dir(path), "app-audit.log")
+			if err := os.WriteFile(other, nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, _ = r.Write(bytes.Repeat([]byte("x"), 1024*1024))
+			_, _ = r.Write([]byte("rotates\n"))
+
+			if got := backupNames(t, r); strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("backups %v, want %v", got, tt.want)
+			}
+			if _, err := os.Stat(other); err != nil {
+				t.Errorf("unrelated file removed: %v", err)
+			}
+		})
+	}
+}
+
+func TestRotateFailureKeepsLogging(t *testing.T) {
+	now, advance := fixedClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local))
+	r, path := openRotating(t, RotationConfig{MaxSizeMB: 1}, now)
+
+	// a directory in place of the backup makes the rename fail
+	blocked := filepath.Join(filepath.Dir(path), "app-2024-05-01T10-00-00.000.log")
+	if err := os.MkdirAll(filepath.Join(blocked, "keep"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = r.Write(bytes.Repeat([]byte("x"), 1024*1024))
+	n, err := r.Write([]byte("after failure\n"))
+	if err == nil || n != len("after failure\n") {
+		t.Fatalf("first write after the failure: %d, %v, want the line written and the error", n, err)
+	}
+	if _, err := r.Write([]byte("still logging\n")); err != nil {
+		t.Errorf("the rotation error must be reported once, got %v", err)
+	}
+
+	advance(rotateRetry)
+	if _, err := r.Write([]byte("rotated\n")); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "rotated\n" {
+		t.Errorf("current file %q, %v", data, err)
+	}
+	backup, err := os.ReadFile(filepath.Join(filepath.Dir(path), "app-2024-05-01T10-01-00.000.log"))
+	if err != nil || !strings.HasSuffix(string(backup), "after failure\nstill logging\n") {
+		t.Errorf("backup ends with %q, %v", backup[max(0, len(backup)-40):], err)
+	}
+}
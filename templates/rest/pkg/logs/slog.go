@@ -0,0 +1,106 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type slogHandler struct {
+	zap *zap.Logger
+}
+
+// NewSlogHandler adapts z to slog. The caller comes from the slog record,
+// attributes are redacted like any other field.
+func NewSlogHandler(z *zap.Logger) slog.Handler {
+	return &slogHandler{zap: z.WithOptions(zap.WithCaller(false))}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.zap.Core().Enabled(zapLevel(level))
+}
+
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	entry := h.zap.Check(zapLevel(record.Level), record.Message)
+	if entry == nil {
+		return nil
+	}
+	if record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		entry.Caller = zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, true)
+	}
+	if !record.Time.IsZero() {
+		entry.Time = record.Time
+	}
+
+	fields := make([]zap.Field, 0, record.NumAttrs()+1)
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		fields = append(fields, zap.String("request_id", requestID))
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = appendAttr(fields, attr)
+		return true
+	})
+	entry.Write(fields...)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make([]zap.Field, 0, len(attrs))
+	for _, attr := range attrs {
+		fields = appendAttr(fields, attr)
+	}
+	return &slogHandler{zap: h.zap.With(fields...)}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{zap: h.zap.With(zap.Namespace(name))}
+}
+
+func zapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case level < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case level < slog.LevelError:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+// appendAttr follows slog rules: empty attributes are dropped, groups without a key are inlined.
+func appendAttr(fields []zap.Field, attr slog.Attr) []zap.Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if attr.Value.Kind() != slog.KindGroup {
+		return append(fields, zap.Any(attr.Key, redactor.Load().Value(attr.Key, attr.Value.Any())))
+	}
+
+	group := attr.Value.Group()
+	if attr.Key == "" {
+		for _, nested := range group {
+			fields = appendAttr(fields, nested)
+		}
+		return fields
+	}
+	if len(group) == 0 {
+		return fields
+	}
+	return append(fields, zap.Object(attr.Key, zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		for _, field := range appendAttr(nil, slog.Attr{Value: slog.GroupValue(group...)}) {
+			field.AddTo(enc)
+		}
+		return nil
+	})))
+}
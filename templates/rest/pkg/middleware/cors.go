@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig allows cross-origin requests from AllowOrigins. Entries are exact origins,
+// "*" or a subdomain wildcard such as "https://*.example.com". An empty list disables CORS.
+// "*" lets any site read the responses, so it cannot be combined with AllowCredentials.
+type CORSConfig struct {
+	AllowOrigins     []string
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+var (
+	defaultCORSMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	defaultCORSHeaders = []string{"Authorization", "Content-Type", "Accept-Language", "X-Request-ID"}
+)
+
+var ErrCORSCredentialsWildcard = errors.New(`CORS: AllowCredentials cannot be used with the "*" origin, list the origins`)
+
+func (cfg CORSConfig) Validate() error {
+	if cfg.AllowCredentials && slices.Contains(cfg.AllowOrigins, "*") {
+		return ErrCORSCredentialsWildcard
+	}
+	return nil
+}
+
+// CORS expects a validated cfg. With "*" it answers with a literal "*", which browsers
+// never accept for credentialed requests.
+func CORS(cfg CORSConfig) gin.HandlerFunc {
+	anyOrigin := slices.Contains(cfg.AllowOrigins, "*")
+	methods := strings.Join(orDefault(cfg.AllowMethods, defaultCORSMethods), ", ")
+	headers := strings.Join(orDefault(cfg.AllowHeaders, defaultCORSHeaders), ", ")
+	expose := strings.Join(cfg.ExposeHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Origin")
+
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if !originAllowed(cfg.AllowOrigins, origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if anyOrigin {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials && !anyOrigin {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+		if expose != "" {
+			c.Header("Access-Control-Expose-Headers", expose)
+		}
+
+		if preflight {
+			c.Header("Access-Control-Allow-Methods", methods)
+			c.Header("Access-Control-Allow-Headers", headers)
+			if cfg.MaxAge > 0 {
+				c.Header("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
+
+func originAllowed(allowed []string, origin string) bool {
+	for _, pattern := range allowed {
+		if pattern == "*" || strings.EqualFold(pattern, origin) {
+			return true
+		}
+		scheme, host, ok := strings.Cut(pattern, "://*.")
+		if !ok {
+			continue
+		}
+		rest, ok := strings.CutPrefix(strings.ToLower(origin), strings.ToLower(scheme)+"://")
+		if ok && strings.HasSuffix(rest, "."+strings.ToLower(host)) {
+			return true
+		}
+	}
+	return false
+}
+
+func orDefault(values, fallback []string) []string {
+	if len(values) == 0 {
+		return fallback
+	}
+	return values
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORSConfigValidate(t *testing.T) {
+	cfg := CORSConfig{AllowOrigins: []string{"*"}, AllowCredentials: true}
+	if err := cfg.Validate(); !errors.Is(err, ErrCORSCredentialsWildcard) {
+		t.Errorf("wildcard with credentials: %v", err)
+	}
+	cfg.AllowOrigins = []string{"https://app.example.com"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("listed origins with credentials: %v", err)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	listed := CORSConfig{
+		AllowOrigins:     []string{"https://app.example.com", "https://*.example.org"},
+		ExposeHeaders:    []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+
+	tests := []struct {
+		name      string
+		cfg       CORSConfig
+		method    string
+		origin    string
+		preflight bool
+		status    int
+		headers   map[string]string
+	}{
+		{name: "same origin", cfg: listed, method: http.MethodGet, status: http.StatusOK,
+			headers: map[string]string{"Access-Control-Allow-Origin": "", "Vary": ""}},
+		{name: "listed origin", cfg: listed, method: http.MethodGet, origin: "https://app.example.com", status: http.StatusOK,
+			headers: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "X-Request-ID",
+				"Vary":                             "Origin",
+			}},
+		{name: "subdomain wildcard", cfg: listed, method: http.MethodGet, origin: "https://eu.shop.example.org", status: http.StatusOK,
+			headers: map[string]string{"Access-Control-Allow-Origin": "https://eu.shop.example.org"}},
+		{name: "wildcard needs a subdomain", cfg: listed, method: http.MethodGet, origin: "https://example.org", status: http.StatusOK,
+			headers: map[string]string{"Access-Control-Allow-Origin": ""}},
+		{name: "other scheme", cfg: listed, method: http.MethodGet, origin: "http://app.example.com", status: http.StatusOK,
+			headers: map[string]string{"Access-Control-Allow-Origin": ""}},
+		{name: "preflight", cfg: listed, method: http.MethodOptions, origin: "https://app.example.com", preflight: true, status: http.StatusNoContent,
+			headers: map[string]string{
+				"Access-Control-Allow-Methods": "GET, POST, PUT, PATCH, DELETE",
+				"Access-Control-Allow-Headers": "Authorization, Content-Type, Accept-Language, X-Request-ID",
+				"Access-Control-Max-Age":       "600",
+			}},
+		{name: "preflight from an unknown origin", cfg: listed, method: http.MethodOptions, origin: "https://evil.example.net", preflight: true, status: http.StatusForbidden,
+			headers: map[string]string{"Access-Control-Allow-Origin": ""}},
+		{name: "any origin", cfg: CORSConfig{AllowOrigins: []string{"*"}}, method: http.MethodGet, origin: "https://anyone.example", status: http.StatusOK,
+			headers: map[string]string{"Access-Control-Allow-Origin": "*", "Access-Control-Allow-Credentials": ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.Use(CORS(tt.cfg))
+			engine.Handle(tt.method, "/items", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			r := httptest.NewRequest(tt.method, "/items", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Errorf("status %d, want %d", w.Code, tt.status)
+			}
+			for name, want := range tt.headers {
+				if got := w.Header().Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	customErrors "gotemplate/pkg/errors"
+	"gotemplate/pkg/ginplugins"
+	"gotemplate/pkg/logs"
+)
+
+// Limit is a token bucket: Rate tokens per second refill a bucket of Burst tokens.
+// Rate must be positive.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+type Decision struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// RateLimitStore keeps buckets. MemoryStore is per process; implement it on top of
+// Redis or a similar store to share limits between replicas.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, limit Limit) (Decision, error)
+}
+
+// KeyFunc selects the bucket of a request, an empty key skips limiting.
+type KeyFunc func(c *gin.Context) string
+
+func KeyByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// KeyByHeader buckets requests by the value of a header, falling back to the client IP.
+// Clients choose their headers and get a fresh bucket with every new value, so use it
+// only for headers set by a trusted proxy or gateway, e.g. an authenticated consumer id.
+func KeyByHeader(name string) KeyFunc {
+	return func(c *gin.Context) string {
+		if value := c.GetHeader(name); value != "" {
+			return "header:" + name + ":" + value
+		}
+		return KeyByIP(c)
+	}
+}
+
+// KeyByRoute limits every route as a whole, whoever calls it.
+func KeyByRoute(c *gin.Context) string {
+	return "route:" + c.Request.Method + " " + c.FullPath()
+}
+
+// SkipRoutes exempts the given routes, in gin's pattern syntax, from key.
+func SkipRoutes(key KeyFunc, routes ...string) KeyFunc {
+	return func(c *gin.Context) string {
+		if slices.Contains(routes, c.FullPath()) {
+			return ""
+		}
+		return key(c)
+	}
+}
+
+// ParseKey understands "ip", "route" and "header:<Name>".
+func ParseKey(spec string) (KeyFunc, error) {
+	switch {
+	case spec == "" || spec == "ip":
+		return KeyByIP, nil
+	case spec == "route":
+		return KeyByRoute, nil
+	case strings.HasPrefix(spec, "header:"):
+		return KeyByHeader(strings.TrimPrefix(spec, "header:")), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit key %q", spec)
+	}
+}
+
+// RateLimit rejects requests over limit with customErrors.ErrRateLimited. When the store
+// fails the request is let through: an outage of the store must not take the API down.
+func RateLimit(store RateLimitStore, limit Limit, key KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bucket := key(c)
+		if bucket == "" {
+			c.Next()
+			return
+		}
+
+		decision, err := store.Take(c.Request.Context(), bucket, limit)
+		if err != nil {
+			logs.WarnCtx(c.Request.Context(), "Rate limit store failed", "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		if !decision.Allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(decision.RetryAfter.Seconds()))))
+			ginplugins.WrapError(customErrors.WrapRateLimitedError(fmt.Errorf("rate limit exceeded for %s", bucket)), c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// MemoryStore keeps token buckets in process memory. Full buckets are dropped
+// periodically, they carry no state worth keeping.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now, limit)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+		return Decision{Allowed: false, RetryAfter: wait}, nil
+	}
+	b.tokens--
+	return Decision{Allowed: true, Remaining: int(b.tokens)}, nil
+}
+
+func (s *MemoryStore) sweep(now time.Time, limit Limit) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*limit.Rate >= float64(limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Rate: 2, Burst: 3}
+	ctx := context.Background()
+
+	for i := range 3 {
+		d, _ := store.Take(ctx, "a", limit)
+		if !d.Allowed || d.Remaining != 2-i {
+			t.Fatalf("request %d within the burst: %+v", i+1, d)
+		}
+	}
+	d, _ := store.Take(ctx, "a", limit)
+	if d.Allowed || d.RetryAfter != 500*time.Millisecond {
+		t.Errorf("over the burst: %+v, want a retry after one token refilled", d)
+	}
+	if d, _ := store.Take(ctx, "b", limit); !d.Allowed {
+		t.Error("buckets are shared between keys")
+	}
+
+	now = now.Add(time.Second)
+	for i := range 2 {
+		if d, _ := store.Take(ctx, "a", limit); !d.Allowed {
+			t.Fatalf("refilled request %d rejected", i+1)
+		}
+	}
+	if d, _ := store.Take(ctx, "a", limit); d.Allowed {
+		t.Error("refill exceeded the rate")
+	}
+
+	// refills never exceed the burst
+	now = now.Add(time.Hour)
+	for range 3 {
+		_, _ = store.Take(ctx, "a", limit)
+	}
+	if d, _ := store.Take(ctx, "a", limit); d.Allowed {
+		t.Error("the bucket held more than Burst tokens")
+	}
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Rate: 1, Burst: 10}
+
+	_, _ = store.Take(context.Background(), "idle", limit)
+	now = now.Add(2 * time.Minute)
+	_, _ = store.Take(context.Background(), "busy", limit)
+
+	if _, ok := store.buckets["idle"]; ok {
+		t.Error("a refilled bucket was kept")
+	}
+	if _, ok := store.buckets["busy"]; !ok {
+		t.Error("the bucket in use was dropped")
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Limit) (Decision, error) {
+	return Decision{}, errors.New("redis is down")
+}
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		store  RateLimitStore
+		path   string
+		header string
+		status []int
+	}{
+		{"limited", NewMemoryStore(), "/api/items", "", []int{200, 200, 429}},
+		{"skipped route", NewMemoryStore(), "/ready", "", []int{200, 200, 200}},
+		{"header buckets", NewMemoryStore(), "/api/items", "consumer-1", []int{200, 200, 429}},
+		{"store outage", failingStore{}, "/api/items", "", []int{200, 200, 200}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			key := SkipRoutes(KeyByHeader("X-Consumer"), "/ready")
+			engine.Use(RateLimit(tt.store, Limit{Rate: 0.001, Burst: 2}, key))
+			engine.GET("/api/items", func(c *gin.Context) { c.Status(http.StatusOK) })
+			engine.GET("/ready", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			for i, want := range tt.status {
+				r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+				if tt.header != "" {
+					r.Header.Set("X-Consumer", tt.header)
+				}
+				w := httptest.NewRecorder()
+				engine.ServeHTTP(w, r)
+				if w.Code != want {
+					t.Fatalf("request %d: status %d, want %d", i+1, w.Code, want)
+				}
+				if want == http.StatusTooManyRequests && (w.Header().Get("Retry-After") == "" || w.Header().Get("X-RateLimit-Remaining") != "0") {
+					t.Errorf("429 headers %v", w.Header())
+				}
+			}
+		})
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	for _, spec := range []string{"", "ip", "route", "header:X-Consumer"} {
+		if _, err := ParseKey(spec); err != nil {
+			t.Errorf("%q: %v", spec, err)
+		}
+	}
+	if _, err := ParseKey("user"); err == nil {
+		t.Error("unknown key accepted")
+	}
+}
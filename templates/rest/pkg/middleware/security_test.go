@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const hsts = "max-age=15552000; includeSubDomains"
+
+	tests := []struct {
+		name    string
+		cfg     SecurityHeadersConfig
+		request func(r *http.Request)
+		headers map[string]string
+	}{
+		{"plain HTTP", DefaultSecurityHeaders, func(*http.Request) {}, map[string]string{
+			"Strict-Transport-Security": "",
+			"Content-Security-Policy":   "default-src 'none'; frame-ancestors 'none'",
+			"X-Frame-Options":           "DENY",
+			"Referrer-Policy":           "no-referrer",
+			"X-Content-Type-Options":    "nosniff",
+		}},
+		{"TLS", DefaultSecurityHeaders, func(r *http.Request) { r.TLS = &tls.ConnectionState{} }, map[string]string{
+			"Strict-Transport-Security": hsts,
+		}},
+		{"behind a proxy", DefaultSecurityHeaders, func(r *http.Request) { r.Header.Set("X-Forwarded-Proto", "https") }, map[string]string{
+			"Strict-Transport-Security": hsts,
+		}},
+		{"preload", SecurityHeadersConfig{HSTSMaxAge: DefaultSecurityHeaders.HSTSMaxAge, HSTSPreload: true},
+			func(r *http.Request) { r.TLS = &tls.ConnectionState{} }, map[string]string{
+				"Strict-Transport-Security": "max-age=15552000; preload",
+				"X-Frame-Options":           "",
+			}},
+		{"disabled", SecurityHeadersConfig{}, func(r *http.Request) { r.TLS = &tls.ConnectionState{} }, map[string]string{
+			"Strict-Transport-Security": "",
+			"Content-Security-Policy":   "",
+			"X-Content-Type-Options":    "",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.Use(SecurityHeaders(tt.cfg))
+			engine.GET("/items", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			r := httptest.NewRequest(http.MethodGet, "/items", nil)
+			tt.request(r)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, r)
+
+			for name, want := range tt.headers {
+				if got := w.Header().Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	customErrors "gotemplate/pkg/errors"
+	"gotemplate/pkg/ginplugins"
+	"gotemplate/pkg/plugins"
+)
+
+const TimezoneHeader = "X-Timezone"
+
+// LocationFunc returns the IANA time zone of the caller, e.g. from the user profile,
+// or an empty string when it is unknown.
+type LocationFunc func(c *gin.Context) string
+
+// Timezone puts the caller's location into the request context for plugins.NowCtx,
+// ParseQueryCtxE and the date formats. profile wins over header; when neither names a
+// zone the service default from TIMEZONE applies. An unknown zone is a validation error.
+//
+// Body and parameter binding does not see the request context: DateFormat and
+// DateTimeFormat parse values without an offset in the TIMEZONE default and render in
+// the zone they hold. Use ParseQueryCtxE for such input and InContext before rendering
+// when the caller's zone matters.
+func Timezone(header string, profile LocationFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := ""
+		if profile != nil {
+			name = profile(c)
+		}
+		if name == "" && header != "" {
+			name = c.GetHeader(header)
+		}
+		if name == "" {
+			c.Next()
+			return
+		}
+
+		loc, err := plugins.LoadLocation(name)
+		if err != nil {
+			ginplugins.WrapError(customErrors.Wrap(
+				fmt.Errorf("load location %q: %w", name, err),
+				customErrors.ErrValidation, "invalid_timezone", "Unknown time zone "+name,
+			), c)
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(plugins.WithLocation(c.Request.Context(), loc))
+		c.Next()
+	}
+}
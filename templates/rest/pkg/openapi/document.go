@@ -0,0 +1,177 @@
+package openapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	customErrors "gotemplate/pkg/errors"
+	"gotemplate/pkg/ginplugins"
+)
+
+const Version = "3.0.3"
+
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]Operation `json:"paths"`
+	Components Components                      `json:"components"`
+
+	mu      sync.RWMutex
+	schemas *schemaRegistry
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Route describes a handler for the generated document. Request is bound from the JSON body,
+// Query from the query string (form tags), Response is what the handler writes on success.
+// Errors lists the pkg/errors categories the handler may return besides ErrSystem.
+type Route struct {
+	OperationID string
+	Summary     string
+	Description string
+	Tags        []string
+	Request     any
+	Query       any
+	Response    any
+	Status      int
+	Errors      []error
+}
+
+func New(title, version string) *Document {
+	schemas := newSchemaRegistry()
+	return &Document{
+		OpenAPI:    Version,
+		Info:       Info{Title: title, Version: version},
+		Paths:      make(map[string]map[string]Operation),
+		Components: Components{Schemas: schemas.components},
+		schemas:    schemas,
+	}
+}
+
+// Add registers a route. ginPath uses gin syntax, e.g. /users/:id.
+func (d *Document) Add(method, ginPath string, route Route) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	path, pathParams := convertPath(ginPath)
+	op := Operation{
+		OperationID: route.OperationID,
+		Summary:     route.Summary,
+		Description: route.Description,
+		Tags:        route.Tags,
+		Responses:   make(map[string]*Response),
+	}
+
+	for _, name := range pathParams {
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	if route.Query != nil {
+		op.Parameters = append(op.Parameters, d.schemas.parameters(route.Query, "query", "form")...)
+	}
+	if route.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: d.schemas.schemaOf(route.Request)}},
+		}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	if route.Response != nil {
+		success.Content = map[string]MediaType{"application/json": {Schema: d.schemas.schemaOf(route.Response)}}
+	}
+	op.Responses[strconv.Itoa(status)] = success
+
+	errs := append([]error{customErrors.ErrSystem}, route.Errors...)
+	if route.Request != nil || route.Query != nil {
+		errs = append(errs, customErrors.ErrValidation)
+	}
+	for _, err := range errs {
+		d.addError(&op, err)
+	}
+
+	if d.Paths[path] == nil {
+		d.Paths[path] = make(map[string]Operation)
+	}
+	d.Paths[path][strings.ToLower(method)] = op
+}
+
+func (d *Document) addError(op *Operation, err error) {
+	_, status := ginplugins.ErrorStatus(err)
+	code := strconv.Itoa(status)
+	if _, exists := op.Responses[code]; exists {
+		return
+	}
+	op.Responses[code] = &Response{
+		Description: http.StatusText(status) + " (" + err.Error() + ")",
+		Content:     map[string]MediaType{ginplugins.ProblemContentType: {Schema: d.schemas.schemaOf(ginplugins.Problem{})}},
+	}
+}
+
+func convertPath(ginPath string) (string, []string) {
+	var params []string
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if len(segment) > 1 && (segment[0] == ':' || segment[0] == '*') {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// Handler serves the document as JSON.
+func (d *Document) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		d.mu.RLock()
+		defer d.mu.RUnlock()
+
+		c.JSON(http.StatusOK, d)
+	}
+}
@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Router registers gin handlers and documents them in the same call.
+type Router struct {
+	group gin.IRouter
+	base  string
+	doc   *Document
+}
+
+func NewRouter(group *gin.RouterGroup, doc *Document) *Router {
+	return &Router{group: group, base: group.BasePath(), doc: doc}
+}
+
+func (r *Router) Group(relativePath string, handlers ...gin.HandlerFunc) *Router {
+	return &Router{
+		group: r.group.Group(relativePath, handlers...),
+		base:  path.Join(r.base, relativePath),
+		doc:   r.doc,
+	}
+}
+
+func (r *Router) Use(middleware ...gin.HandlerFunc) *Router {
+	r.group.Use(middleware...)
+	return r
+}
+
+func (r *Router) Handle(method, relativePath string, route Route, handlers ...gin.HandlerFunc) {
+	r.group.Handle(method, relativePath, handlers...)
+	r.doc.Add(method, path.Join(r.base, relativePath), route)
+}
+
+func (r *Router) GET(relativePath string, route Route, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodGet, relativePath, route, handlers...)
+}
+
+func (r *Router) POST(relativePath string, route Route, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodPost, relativePath, route, handlers...)
+}
+
+func (r *Router) PUT(relativePath string, route Route, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodPut, relativePath, route, handlers...)
+}
+
+func (r *Router) PATCH(relativePath string, route Route, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodPatch, relativePath, route, handlers...)
+}
+
+func (r *Router) DELETE(relativePath string, route Route, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodDelete, relativePath, route, handlers...)
+}
@@ -0,0 +1,229 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"gotemplate/pkg/plugins"
+)
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Example              string             `json:"example,omitempty"`
+}
+
+var knownTypes = map[reflect.Type]Schema{
+	reflect.TypeFor[time.Time]():              {Type: "string", Format: "date-time"},
+	reflect.TypeFor[time.Duration]():          {Type: "string", Example: "1m30s"},
+	reflect.TypeFor[plugins.DateFormat]():     {Type: "string", Format: "date", Example: "2024-01-31"},
+	reflect.TypeFor[plugins.DateTimeFormat](): {Type: "string", Format: "date-time"},
+	reflect.TypeFor[plugins.Decimal]():        {Type: "string", Format: "decimal", Example: "12.30"},
+	reflect.TypeFor[json.RawMessage]():        {},
+	reflect.TypeFor[map[string]any]():         {Type: "object"},
+	reflect.TypeFor[[]byte]():                 {Type: "string", Format: "byte"},
+}
+
+// RegisterType overrides the schema generated for t, e.g. for types with custom JSON encoding.
+func RegisterType(t reflect.Type, schema Schema) {
+	knownTypes[t] = schema
+}
+
+var ownPackage = reflect.TypeFor[Schema]().PkgPath()
+
+type schemaRegistry struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+func (r *schemaRegistry) schemaOf(v any) *Schema {
+	return r.schema(reflect.TypeOf(v))
+}
+
+func (r *schemaRegistry) schema(t reflect.Type) *Schema {
+	if known, ok := knownTypes[t]; ok {
+		return &known
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := r.schema(t.Elem())
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: r.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schema(t.Elem())}
+	case reflect.Struct:
+		return r.structRef(t)
+	default:
+		return &Schema{}
+	}
+}
+
+// structRef places named structs into components and returns a reference to them.
+func (r *schemaRegistry) structRef(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return r.structSchema(t)
+	}
+
+	name, ok := r.names[t]
+	if !ok {
+		name = r.componentName(t)
+		r.names[t] = name
+		r.components[name] = r.structSchema(t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (r *schemaRegistry) componentName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+	name := t.Name()
+	if pkg != "" && t.PkgPath() != ownPackage {
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	// generic instantiations carry their type arguments in the name
+	name = strings.NewReplacer("[", "_", "]", "", "*", "", "/", "_", ".", "_", ",", "_").Replace(name)
+	return name
+}
+
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	r.collectFields(t, s)
+	return s
+}
+
+func (r *schemaRegistry) collectFields(t reflect.Type, s *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, skip := jsonName(field)
+		if skip {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				r.collectFields(embedded, s)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := r.schema(field.Type)
+		if description := field.Tag.Get("description"); description != "" {
+			prop = withDescription(prop, description)
+		}
+		if example := field.Tag.Get("example"); example != "" {
+			prop.Example = example
+		}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			prop.Enum = strings.Split(enum, ",")
+		}
+		s.Properties[name] = prop
+
+		if isRequired(field) {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// parameters turns a struct bound with ShouldBindQuery/ShouldBindUri into parameter objects.
+func (r *schemaRegistry) parameters(v any, in, tagName string) []Parameter {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var params []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get(tagName), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		params = append(params, Parameter{
+			Name:        name,
+			In:          in,
+			Description: field.Tag.Get("description"),
+			Required:    isRequired(field),
+			Schema:      r.schema(field.Type),
+		})
+	}
+	return params
+}
+
+func jsonName(field reflect.StructField) (name string, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name, _, _ = strings.Cut(tag, ",")
+	return name, false
+}
+
+func isRequired(field reflect.StructField) bool {
+	for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// withDescription keeps $ref schemas valid: siblings of $ref are ignored in OpenAPI 3.0.
+func withDescription(s *Schema, description string) *Schema {
+	if s.Ref != "" {
+		return s
+	}
+	s.Description = description
+	return s
+}
@@ -0,0 +1,69 @@
+package plugins
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clock is the source of the current time. Inject it instead of calling time.Now
+// so that time-dependent code can run against a FakeClock in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+type RealClock struct{}
+
+func (RealClock) Now() time.Time { return time.Now() }
+
+type clockHolder struct{ Clock }
+
+var clock atomic.Pointer[clockHolder]
+
+func init() {
+	clock.Store(&clockHolder{RealClock{}})
+}
+
+// SetClock installs the clock behind GetNow and NowCtx. NewApp passes the injected
+// clock, so the package helpers and the components read the same time.
+func SetClock(c Clock) {
+	clock.Store(&clockHolder{c})
+}
+
+// FakeClock stands still until it is set or advanced. With a step, every Now call
+// advances it, which keeps timestamps of consecutive events distinct.
+type FakeClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func (c *FakeClock) SetStep(step time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.step = step
+}
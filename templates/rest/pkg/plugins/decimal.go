@@ -0,0 +1,624 @@
+package plugins
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MaxDecimalScale is the largest number of fractional digits a Decimal keeps.
+const MaxDecimalScale = 18
+
+var (
+	ErrDecimalOverflow       = errors.New("decimal overflow")
+	ErrDecimalSyntax         = errors.New("invalid decimal")
+	ErrDecimalDivisionByZero = errors.New("decimal division by zero")
+)
+
+// decimalInputScale limits the fractional digits of decimals read from clients.
+var decimalInputScale int32 = 9
+
+// SetDecimalInputScale changes how many fractional digits UnmarshalJSON, UnmarshalText and
+// UnmarshalParam accept, 9 by default. ParseDecimal and Scan take up to MaxDecimalScale.
+func SetDecimalInputScale(scale int32) {
+	if scale < 0 || scale > MaxDecimalScale {
+		panic(fmt.Sprintf("plugins: decimal scale %d is out of range", scale))
+	}
+	decimalInputScale = scale
+}
+
+type RoundingMode int
+
+const (
+	// RoundHalfEven is banker's rounding: ties go to the even neighbour, 2.345 -> 2.34.
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds ties away from zero, 2.345 -> 2.35.
+	RoundHalfUp
+	// RoundDown truncates towards zero.
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+)
+
+// Decimal is a fixed-point number: units scaled by 10^-scale, e.g. {1230, 2} is 12.30.
+// It covers about ±9.2e18 units, plenty for money. Add and Mul drop fractional digits
+// that do not fit; operations whose integer part does not fit panic with ErrDecimalOverflow,
+// like integer division by zero does. The variants ending in E return the error instead.
+// The zero value is 0.
+type Decimal struct {
+	units int64
+	scale int32
+}
+
+var pow10 = [MaxDecimalScale + 1]int64{
+	1, 10, 100, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9,
+	1e10, 1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18,
+}
+
+// NewDecimal returns units * 10^-scale, NewDecimal(1230, 2) is 12.30.
+func NewDecimal(units int64, scale int32) Decimal {
+	if scale < 0 || scale > MaxDecimalScale {
+		panic(fmt.Sprintf("plugins: decimal scale %d is out of range", scale))
+	}
+	return Decimal{units: units, scale: scale}
+}
+
+func NewDecimalFromInt(i int64) Decimal {
+	return Decimal{units: i}
+}
+
+// NewDecimalFromFloat converts f through its shortest decimal representation, so 0.1
+// becomes exactly 0.1. Digits beyond MaxDecimalScale are rounded half-even.
+func NewDecimalFromFloat(f float64) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("%w: %v", ErrDecimalSyntax, f)
+	}
+	var buf [32]byte
+	return parseDecimal(strconv.AppendFloat(buf[:0], f, 'f', -1, 64), MaxDecimalScale, true)
+}
+
+// ParseDecimal reads plain notation such as "-12.30", exponents are not accepted. The scale
+// of the result is the number of fractional digits given, so "12.30" keeps scale 2.
+func ParseDecimal(s string) (Decimal, error) {
+	return parseDecimal([]byte(s), MaxDecimalScale, false)
+}
+
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// parseDecimal reads at most maxScale fractional digits, the rest is rounded half-even
+// with roundExcess and rejected without.
+func parseDecimal(s []byte, maxScale int32, roundExcess bool) (Decimal, error) {
+	fail := func(err error) (Decimal, error) {
+		return Decimal{}, fmt.Errorf("%w: %q", err, string(s))
+	}
+
+	i, neg := 0, false
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		i++
+	}
+
+	var units uint64
+	var scale int32
+	digits, fraction := 0, false
+	roundDigit, sticky := -1, false
+	for ; i < len(s); i++ {
+		c := s[i]
+		if c == '.' && !fraction {
+			fraction = true
+			continue
+		}
+		if c < '0' || c > '9' {
+			return fail(ErrDecimalSyntax)
+		}
+		digits++
+		if fraction && scale == maxScale {
+			if !roundExcess {
+				return Decimal{}, fmt.Errorf("%w: %q has more than %d fractional digits", ErrDecimalSyntax, string(s), maxScale)
+			}
+			if roundDigit < 0 {
+				roundDigit = int(c - '0')
+			} else if c != '0' {
+				sticky = true
+			}
+			continue
+		}
+		hi, lo := bits.Mul64(units, 10)
+		lo, carry := bits.Add64(lo, uint64(c-'0'), 0)
+		if hi != 0 || carry != 0 || lo > math.MaxInt64 {
+			return fail(ErrDecimalOverflow)
+		}
+		units = lo
+		if fraction {
+			scale++
+		}
+	}
+	if digits == 0 {
+		return fail(ErrDecimalSyntax)
+	}
+
+	if roundDigit > 5 || roundDigit == 5 && (sticky || units%2 == 1) {
+		if units == math.MaxInt64 {
+			return fail(ErrDecimalOverflow)
+		}
+		units++
+	}
+	d := Decimal{units: int64(units), scale: scale}
+	if neg {
+		d.units = -d.units
+	}
+	return d, nil
+}
+
+func (d Decimal) Units() int64   { return d.units }
+func (d Decimal) Scale() int32   { return d.scale }
+func (d Decimal) IsZero() bool   { return d.units == 0 }
+func (d Decimal) Neg() Decimal   { return Decimal{units: -d.units, scale: d.scale} }
+func (d Decimal) Sign() int      { return cmpInt(d.units, 0) }
+func (d Decimal) Abs() Decimal   { return Decimal{units: abs(d.units), scale: d.scale} }
+func (d Decimal) Int64() int64   { return d.units / pow10[d.scale] }
+func (d Decimal) String() string { return string(d.append(nil)) }
+
+func (d Decimal) Float64() float64 {
+	if d.units > -(1<<53) && d.units < 1<<53 {
+		// both operands are exact, so the quotient is correctly rounded
+		return float64(d.units) / float64(pow10[d.scale])
+	}
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+func (d Decimal) append(buf []byte) []byte {
+	if d.units < 0 {
+		buf = append(buf, '-')
+	}
+	u := uint64(d.units)
+	if d.units < 0 {
+		u = -u
+	}
+	var digits [20]byte
+	n := len(strconv.AppendUint(digits[:0], u, 10))
+	s := digits[:n]
+	if int(d.scale) >= n {
+		buf = append(buf, '0', '.')
+		for range int(d.scale) - n {
+			buf = append(buf, '0')
+		}
+		return append(buf, s...)
+	}
+	buf = append(buf, s[:n-int(d.scale)]...)
+	if d.scale > 0 {
+		buf = append(buf, '.')
+		buf = append(buf, s[n-int(d.scale):]...)
+	}
+	return buf
+}
+
+// Rescale changes the scale, rounding with mode when digits are dropped.
+func (d Decimal) Rescale(scale int32, mode RoundingMode) Decimal {
+	return must(d.RescaleE(scale, mode))
+}
+
+// RescaleE is Rescale returning ErrDecimalOverflow when added digits do not fit.
+func (d Decimal) RescaleE(scale int32, mode RoundingMode) (Decimal, error) {
+	switch {
+	case scale < 0 || scale > MaxDecimalScale:
+		panic(fmt.Sprintf("plugins: decimal scale %d is out of range", scale))
+	case scale == d.scale:
+		return d, nil
+	case scale > d.scale:
+		units, ok := mulInt(d.units, pow10[scale-d.scale])
+		if !ok {
+			return Decimal{}, ErrDecimalOverflow
+		}
+		return Decimal{units: units, scale: scale}, nil
+	}
+
+	div := pow10[d.scale-scale]
+	q, r := d.units/div, d.units%div
+	if roundAway(abs(r), div, q, mode) {
+		if d.units < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return Decimal{units: q, scale: scale}, nil
+}
+
+// Round rounds to scale fractional digits. Unlike Rescale it never adds digits.
+func (d Decimal) Round(scale int32, mode RoundingMode) Decimal {
+	if scale >= d.scale {
+		return d
+	}
+	return d.Rescale(scale, mode)
+}
+
+// roundAway decides whether the truncated quotient q moves away from zero for remainder r of div.
+func roundAway(r, div, q int64, mode RoundingMode) bool {
+	if r == 0 {
+		return false
+	}
+	switch mode {
+	case RoundDown:
+		return false
+	case RoundUp:
+		return true
+	}
+	half := div - r // compare 2r with div without overflow
+	switch {
+	case r > half:
+		return true
+	case r < half:
+		return false
+	case mode == RoundHalfUp:
+		return true
+	default:
+		return q%2 != 0
+	}
+}
+
+// Add is exact when the sum fits at the larger scale of both, otherwise it keeps as many
+// fractional digits as fit, rounded half-even.
+func (d Decimal) Add(other Decimal) Decimal {
+	return must(d.AddE(other))
+}
+
+// AddE is Add returning ErrDecimalOverflow when the integer part does not fit.
+func (d Decimal) AddE(other Decimal) (Decimal, error) {
+	if a, b, ok := align(d, other); ok {
+		if sum, overflow := addInt(a.units, b.units); !overflow {
+			return Decimal{units: sum, scale: a.scale}, nil
+		}
+	}
+	scale := max(d.scale, other.scale)
+	return fitDecimal(new(big.Int).Add(scaledUnits(d, scale), scaledUnits(other, scale)), scale)
+}
+
+func (d Decimal) Sub(other Decimal) Decimal {
+	return must(d.SubE(other))
+}
+
+func (d Decimal) SubE(other Decimal) (Decimal, error) {
+	return d.AddE(other.Neg())
+}
+
+// Mul is exact when the product fits, otherwise it keeps as many fractional digits as fit,
+// rounded half-even.
+func (d Decimal) Mul(other Decimal) Decimal {
+	return must(d.MulE(other))
+}
+
+// MulE is Mul returning ErrDecimalOverflow when the integer part does not fit.
+func (d Decimal) MulE(other Decimal) (Decimal, error) {
+	neg := (d.units < 0) != (other.units < 0)
+	hi, lo := bits.Mul64(uint64(abs(d.units)), uint64(abs(other.units)))
+	scale := d.scale + other.scale
+
+	units := lo
+	if hi != 0 || lo > math.MaxInt64 || scale > MaxDecimalScale {
+		drop := max(scale-MaxDecimalScale, 0)
+		for {
+			if drop > scale || drop > MaxDecimalScale {
+				return Decimal{}, ErrDecimalOverflow
+			}
+			div := uint64(pow10[drop])
+			if hi < div {
+				q, r := bits.Div64(hi, lo, div)
+				if roundAway(int64(r), int64(div), int64(q&1), RoundHalfEven) {
+					q++
+				}
+				if q <= math.MaxInt64 {
+					units = q
+					break
+				}
+			}
+			drop++
+		}
+		scale -= drop
+	}
+
+	if neg {
+		return Decimal{units: -int64(units), scale: scale}, nil
+	}
+	return Decimal{units: int64(units), scale: scale}, nil
+}
+
+// Div divides to scale fractional digits rounded with mode. It panics when other is zero.
+func (d Decimal) Div(other Decimal, scale int32, mode RoundingMode) Decimal {
+	return must(d.DivE(other, scale, mode))
+}
+
+// DivE is Div returning ErrDecimalDivisionByZero and ErrDecimalOverflow.
+func (d Decimal) DivE(other Decimal, scale int32, mode RoundingMode) (Decimal, error) {
+	if other.units == 0 {
+		return Decimal{}, ErrDecimalDivisionByZero
+	}
+	// d/other = d.units * 10^(scale + other.scale - d.scale) / other.units, in units of 10^-scale
+	num := big.NewInt(d.units)
+	den := big.NewInt(other.units)
+	if exp := scale + other.scale - d.scale; exp >= 0 {
+		num.Mul(num, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil))
+	} else {
+		den.Mul(den, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exp)), nil))
+	}
+	neg := num.Sign()*den.Sign() < 0
+	num.Abs(num)
+	den.Abs(den)
+
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() != 0 {
+		twice := new(big.Int).Lsh(r, 1)
+		c := twice.Cmp(den)
+		up := false
+		switch mode {
+		case RoundUp:
+			up = true
+		case RoundHalfUp:
+			up = c >= 0
+		case RoundHalfEven:
+			up = c > 0 || c == 0 && q.Bit(0) == 1
+		}
+		if up {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	if !q.IsInt64() {
+		return Decimal{}, ErrDecimalOverflow
+	}
+	units := q.Int64()
+	if neg {
+		units = -units
+	}
+	return NewDecimal(units, scale), nil
+}
+
+// Cmp returns -1, 0 or +1. Scale does not matter: 1.5 equals 1.50.
+func (d Decimal) Cmp(other Decimal) int {
+	if d.scale == other.scale {
+		return cmpInt(d.units, other.units)
+	}
+	// compare through Mul64 so aligning scales cannot overflow
+	a, b := d, other
+	sign := 1
+	if a.scale > b.scale {
+		a, b, sign = b, a, -1
+	}
+	if a.Sign() != b.Sign() {
+		return sign * cmpInt(int64(a.Sign()), int64(b.Sign()))
+	}
+	hi, lo := bits.Mul64(uint64(abs(a.units)), uint64(pow10[b.scale-a.scale]))
+	c := 1
+	if hi == 0 {
+		c = cmpUint(lo, uint64(abs(b.units)))
+	}
+	if a.units < 0 {
+		c = -c
+	}
+	return sign * c
+}
+
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+func must(d Decimal, err error) Decimal {
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// align brings a and b to the larger scale, ok is false when that does not fit.
+func align(a, b Decimal) (Decimal, Decimal, bool) {
+	var err error
+	switch {
+	case a.scale < b.scale:
+		a, err = a.RescaleE(b.scale, RoundDown)
+	case a.scale > b.scale:
+		b, err = b.RescaleE(a.scale, RoundDown)
+	}
+	return a, b, err == nil
+}
+
+func scaledUnits(d Decimal, scale int32) *big.Int {
+	return new(big.Int).Mul(big.NewInt(d.units), big.NewInt(pow10[scale-d.scale]))
+}
+
+// fitDecimal returns units * 10^-scale with as many fractional digits as fit, rounded half-even.
+func fitDecimal(units *big.Int, scale int32) (Decimal, error) {
+	for drop := int32(0); drop <= scale; drop++ {
+		q := units
+		if drop > 0 {
+			div, r := big.NewInt(pow10[drop]), new(big.Int)
+			q, r = new(big.Int).QuoRem(units, div, r)
+			// QuoRem truncates towards zero, ties go to the even quotient
+			if c := r.Abs(r).Lsh(r, 1).Cmp(div); c > 0 || c == 0 && q.Bit(0) == 1 {
+				q.Add(q, big.NewInt(int64(units.Sign())))
+			}
+		}
+		if q.IsInt64() {
+			return Decimal{units: q.Int64(), scale: scale - drop}, nil
+		}
+	}
+	return Decimal{}, ErrDecimalOverflow
+}
+
+func mulInt(a, b int64) (int64, bool) {
+	hi, lo := bits.Mul64(uint64(abs(a)), uint64(b))
+	if hi != 0 || lo > math.MaxInt64 {
+		return 0, false
+	}
+	if a < 0 {
+		return -int64(lo), true
+	}
+	return int64(lo), true
+}
+
+func addInt(a, b int64) (int64, bool) {
+	sum := a + b
+	return sum, (a >= 0) == (b >= 0) && (sum >= 0) != (a >= 0)
+}
+
+func abs(i int64) int64 {
+	if i < 0 {
+		if i == math.MinInt64 {
+			panic(ErrDecimalOverflow)
+		}
+		return -i
+	}
+	return i
+}
+
+func cmpInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func cmpUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+var (
+	currencyMu     sync.RWMutex
+	currencyScales = map[string]int32{
+		"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+		"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+		"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	}
+)
+
+// CurrencyScale returns the number of minor unit digits of an ISO 4217 currency, 2 for
+// currencies that are not listed.
+func CurrencyScale(code string) int32 {
+	currencyMu.RLock()
+	defer currencyMu.RUnlock()
+	if scale, ok := currencyScales[strings.ToUpper(code)]; ok {
+		return scale
+	}
+	return 2
+}
+
+// RegisterCurrency overrides the scale of a currency, e.g. for internal units with 4 digits.
+func RegisterCurrency(code string, scale int32) {
+	currencyMu.Lock()
+	defer currencyMu.Unlock()
+	currencyScales[strings.ToUpper(code)] = scale
+}
+
+// RoundCurrency rounds to the minor units of currency, 1.005 USD -> 1.00 or 1.01 depending on mode.
+func (d Decimal) RoundCurrency(currency string, mode RoundingMode) Decimal {
+	return d.Rescale(CurrencyScale(currency), mode)
+}
+
+// MarshalJSON writes a string, "12.30", so clients parsing JSON numbers as floats keep every digit.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	buf := append(make([]byte, 0, 24), '"')
+	return append(d.append(buf), '"'), nil
+}
+
+// UnmarshalJSON accepts strings and numbers. null leaves d unchanged, like encoding/json does for other types.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, `"`), `"`)
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %q", ErrDecimalSyntax, s)
+		}
+		v, err := NewDecimalFromFloat(f)
+		if err != nil {
+			return err
+		}
+		if v.scale > decimalInputScale {
+			return fmt.Errorf("%w: %q has more than %d fractional digits", ErrDecimalSyntax, s, decimalInputScale)
+		}
+		*d = v
+		return nil
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return d.append(nil), nil
+}
+
+// UnmarshalText accepts up to the SetDecimalInputScale fractional digits.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	v, err := parseDecimal(text, decimalInputScale, false)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// UnmarshalParam binds the amount from gin query, form and uri parameters.
+func (d *Decimal) UnmarshalParam(param string) error {
+	return d.UnmarshalText([]byte(param))
+}
+
+// Value stores the decimal as a string, which NUMERIC columns take without loss.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+func (d *Decimal) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Decimal{}
+		return nil
+	case int64:
+		*d = NewDecimalFromInt(v)
+		return nil
+	case float64:
+		f, err := NewDecimalFromFloat(v)
+		if err != nil {
+			return err
+		}
+		*d = f
+		return nil
+	case string:
+		return d.scan([]byte(v))
+	case []byte:
+		return d.scan(v)
+	default:
+		return fmt.Errorf("cannot scan %T into a decimal", src)
+	}
+}
+
+// scan reads the column in full, the scale of the database is trusted.
+func (d *Decimal) scan(text []byte) error {
+	v, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
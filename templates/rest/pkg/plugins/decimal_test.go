@@ -0,0 +1,184 @@
+package plugins
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/tidwall/gjson"
+)
+
+func TestDecimalArithmetic(t *testing.T) {
+	d := MustParseDecimal
+	tests := []struct {
+		name string
+		got  Decimal
+		want string
+	}{
+		{"add aligns scales", d("12.30").Add(d("0.005")), "12.305"},
+		{"add drops digits that do not fit", d("100000000000.00").Add(d("0.000000001")), "100000000000.0000000"},
+		{"add rounds dropped digits", d("-100000000000.00").Sub(d("0.000000051")), "-100000000000.0000001"},
+		{"sub", d("1").Sub(d("0.01")), "0.99"},
+		{"mul exact", d("19.99").Mul(d("3")), "59.97"},
+		{"mul negative", d("-1.5").Mul(d("2.25")), "-3.375"},
+		{"mul rounds past max scale", d("0.000000001").Mul(d("0.0000000015")), "0.000000000000000002"},
+		{"div", d("10").Div(d("3"), 2, RoundHalfUp), "3.33"},
+		{"div negative", d("-2").Div(d("3"), 2, RoundHalfEven), "-0.67"},
+		{"half even tie down", d("2.345").Round(2, RoundHalfEven), "2.34"},
+		{"half even tie up", d("2.355").Round(2, RoundHalfEven), "2.36"},
+		{"half up", d("2.345").Round(2, RoundHalfUp), "2.35"},
+		{"half up negative", d("-2.345").Round(2, RoundHalfUp), "-2.35"},
+		{"down", d("-2.349").Round(2, RoundDown), "-2.34"},
+		{"up", d("2.341").Round(2, RoundUp), "2.35"},
+		{"rescale up", d("5").Rescale(2, RoundDown), "5.00"},
+		{"currency", d("1234.5678").RoundCurrency("JPY", RoundHalfUp), "1235"},
+		{"currency 3 digits", d("1.23456").RoundCurrency("kwd", RoundHalfEven), "1.235"},
+		{"small", NewDecimal(5, 4), "0.0005"},
+	}
+	for _, tt := range tests {
+		if tt.got.String() != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if d("1.5").Cmp(d("1.50")) != 0 || d("-1").Cmp(d("0.5")) != -1 || d("10").Cmp(d("9.99")) != 1 {
+		t.Error("Cmp ignores scale incorrectly")
+	}
+}
+
+func TestDecimalOverflowAndSyntax(t *testing.T) {
+	for _, s := range []string{"", "-", "1.2.3", "1e3", "abc", "0.1234567890123456789"} {
+		if _, err := ParseDecimal(s); !errors.Is(err, ErrDecimalSyntax) {
+			t.Errorf("%q: expected syntax error, got %v", s, err)
+		}
+	}
+	if _, err := ParseDecimal("99999999999999999999"); !errors.Is(err, ErrDecimalOverflow) {
+		t.Errorf("expected overflow, got %v", err)
+	}
+
+	defer func() {
+		if r := recover(); r != ErrDecimalOverflow {
+			t.Errorf("expected an overflow panic, got %v", r)
+		}
+	}()
+	NewDecimalFromInt(1 << 62).Add(NewDecimalFromInt(1 << 62))
+}
+
+func TestDecimalErrorVariants(t *testing.T) {
+	d := MustParseDecimal
+	if _, err := NewDecimalFromInt(1 << 62).AddE(NewDecimalFromInt(1 << 62)); !errors.Is(err, ErrDecimalOverflow) {
+		t.Errorf("AddE: expected overflow, got %v", err)
+	}
+	if _, err := NewDecimalFromInt(1 << 62).MulE(d("4")); !errors.Is(err, ErrDecimalOverflow) {
+		t.Errorf("MulE: expected overflow, got %v", err)
+	}
+	if _, err := d("1").DivE(d("0"), 2, RoundHalfEven); !errors.Is(err, ErrDecimalDivisionByZero) {
+		t.Errorf("DivE: expected division by zero, got %v", err)
+	}
+	if _, err := NewDecimalFromInt(1<<62).RescaleE(2, RoundDown); !errors.Is(err, ErrDecimalOverflow) {
+		t.Errorf("RescaleE: expected overflow, got %v", err)
+	}
+	if got, err := d("0.1").SubE(d("0.3")); err != nil || got.String() != "-0.2" {
+		t.Errorf("SubE: got %s, %v", got, err)
+	}
+}
+
+func TestDecimalInputScale(t *testing.T) {
+	var v Decimal
+	for _, input := range []string{`"0.0000000001"`, `0.0000000001`, `1e-10`} {
+		if err := json.Unmarshal([]byte(input), &v); !errors.Is(err, ErrDecimalSyntax) {
+			t.Errorf("%s: expected the input scale to be enforced, got %v", input, err)
+		}
+	}
+	if err := v.UnmarshalParam("0.000000001"); err != nil || v.String() != "0.000000001" {
+		t.Errorf("UnmarshalParam: got %s, %v", v, err)
+	}
+	if err := v.Scan("0.000000000000000001"); err != nil || v.Scale() != MaxDecimalScale {
+		t.Errorf("Scan: got %s, %v", v, err)
+	}
+
+	SetDecimalInputScale(2)
+	t.Cleanup(func() { SetDecimalInputScale(9) })
+	if err := v.UnmarshalParam("1.005"); !errors.Is(err, ErrDecimalSyntax) {
+		t.Errorf("expected 1.005 to be rejected with scale 2, got %v", err)
+	}
+}
+
+func TestDecimalCodecs(t *testing.T) {
+	var invoice struct {
+		Total Decimal  `json:"total"`
+		Tax   *Decimal `json:"tax"`
+	}
+	if err := json.Unmarshal([]byte(`{"total": 12.30, "tax": "1.05"}`), &invoice); err != nil {
+		t.Fatal(err)
+	}
+	out, _ := json.Marshal(invoice)
+	if string(out) != `{"total":"12.30","tax":"1.05"}` {
+		t.Errorf("got %s", out)
+	}
+
+	var scanned Decimal
+	if err := scanned.Scan([]byte("-0.10")); err != nil || scanned.String() != "-0.10" {
+		t.Errorf("scan: %s, %v", scanned, err)
+	}
+	if v, _ := scanned.Value(); v != "-0.10" {
+		t.Errorf("value: %v", v)
+	}
+
+	m := gjson.Parse(`{"Amount": 99.95, "bad": "x"}`).Map()
+	if got := SafeGet[Decimal](m, "amount"); got.String() != "99.95" {
+		t.Errorf("SafeGet: %s", got)
+	}
+	if _, err := SafeGetE[Decimal](m, "bad"); err == nil {
+		t.Error("SafeGetE accepted an invalid amount")
+	}
+}
+
+func TestRoundTo(t *testing.T) {
+	tests := []struct {
+		in        float64
+		precision int
+		want      float64
+	}{
+		{1.2345, 2, 1.23},
+		{2.675, 2, 2.68},
+		{0.125, 2, 0.12},
+		{-1.005, 2, -1},
+		{1e20, 2, 1e20},
+		{123.456, 0, 123},
+	}
+	for _, tt := range tests {
+		if got := RoundTo(tt.in, tt.precision); got != tt.want {
+			t.Errorf("RoundTo(%v, %d) = %v, want %v", tt.in, tt.precision, got, tt.want)
+		}
+	}
+}
+
+// roundToSprintf is the former RoundTo, kept to compare against.
+func roundToSprintf(a float64, precision int) float64 {
+	s, _ := strconv.ParseFloat(fmt.Sprintf("%."+strconv.Itoa(precision)+"f", a), 64)
+	return s
+}
+
+var roundSink float64
+
+func BenchmarkRoundTo(b *testing.B) {
+	for i := range b.N {
+		roundSink = RoundTo(1234.5678+float64(i%100), 2)
+	}
+}
+
+func BenchmarkRoundToSprintf(b *testing.B) {
+	for i := range b.N {
+		roundSink = roundToSprintf(1234.5678+float64(i%100), 2)
+	}
+}
+
+func BenchmarkDecimalMul(b *testing.B) {
+	price, qty := MustParseDecimal("19.99"), MustParseDecimal("3.5")
+	for range b.N {
+		_ = price.Mul(qty).RoundCurrency("USD", RoundHalfEven)
+	}
+}
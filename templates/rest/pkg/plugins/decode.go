@@ -0,0 +1,160 @@
+package plugins
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// DecodeError lists what Decode could not fill. The target is still populated with
+// everything else, so callers may choose to go on with a partial result.
+type DecodeError struct {
+	// Missing holds paths of required fields absent from the payload.
+	Missing []string
+	Invalid []*ParseError
+}
+
+func (e *DecodeError) Error() string {
+	var parts []string
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing "+strings.Join(e.Missing, ", "))
+	}
+	for _, invalid := range e.Invalid {
+		parts = append(parts, invalid.Error())
+	}
+	return "decode: " + strings.Join(parts, "; ")
+}
+
+func (e *DecodeError) Unwrap() []error {
+	errs := make([]error, len(e.Invalid))
+	for i, invalid := range e.Invalid {
+		errs[i] = invalid
+	}
+	return errs
+}
+
+// Decode fills the struct target points to from json with the lookup rules of SafeGet:
+// keys match case-insensitively and may be dotted paths with array indexes.
+//
+//	type Company struct {
+//		INN       string       `json:"inn" alt:"taxId,tin"`
+//		Founded   DateFormat   `json:"founded"`
+//		Director  string       `json:"management.0.name"`
+//		Employees []Employee   `json:"employees"`
+//		Currency  string       `json:"currency" default:"USD"`
+//		Note      *string      `json:"note"`
+//	}
+//
+// alt lists fallback keys tried in order. Absent fields take their default; without one
+// they are reported as missing unless they are pointers or tagged omitempty. The result is
+// a *DecodeError, wrap it into the category that fits, e.g. WrapExternalServiceError for
+// upstream payloads.
+func Decode(json string, target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("plugins: Decode needs a non-nil pointer to a struct")
+	}
+
+	result := &DecodeError{}
+	decodeStruct(v.Elem(), gjson.Parse(json), "", result)
+	if len(result.Missing) == 0 && len(result.Invalid) == 0 {
+		return nil
+	}
+	return result
+}
+
+func decodeStruct(v reflect.Value, object gjson.Result, prefix string, result *DecodeError) {
+	t := v.Type()
+	m := object.Map()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			decodeStruct(v.Field(i), object, prefix, result)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		keys := []string{name}
+		if alt := field.Tag.Get("alt"); alt != "" {
+			keys = append(keys, strings.Split(alt, ",")...)
+		}
+		path := prefix + name
+
+		var value *gjson.Result
+		for _, key := range keys {
+			if found, ok := GetJSONKeyInsensitive(m, strings.TrimSpace(key)); ok {
+				value = found
+				break
+			}
+		}
+
+		switch {
+		case value != nil:
+			decodeValue(v.Field(i), *value, path, result)
+		case field.Tag.Get("default") != "":
+			if err := parseInto(v.Field(i), path, field.Tag.Get("default"), defaultParseOptions()); err != nil {
+				result.Invalid = append(result.Invalid, asParseError(err))
+			}
+		case field.Type.Kind() != reflect.Pointer && !strings.Contains(options, "omitempty"):
+			result.Missing = append(result.Missing, path)
+		}
+	}
+}
+
+func decodeValue(v reflect.Value, value gjson.Result, path string, result *DecodeError) {
+	t := v.Type()
+	switch {
+	case t == gjsonMapType:
+		v.Set(reflect.ValueOf(value.Map()))
+	case t.Kind() == reflect.Pointer:
+		elem := reflect.New(t.Elem())
+		decodeValue(elem.Elem(), value, path, result)
+		v.Set(elem)
+	case t.Kind() == reflect.Struct && !isScalar(t):
+		if !value.IsObject() {
+			result.Invalid = append(result.Invalid, &ParseError{Key: path, Value: value.String(), Type: "object"})
+			return
+		}
+		decodeStruct(v, value, path+".", result)
+	case t.Kind() == reflect.Slice && value.IsArray():
+		items := value.Array()
+		slice := reflect.MakeSlice(t, len(items), len(items))
+		for i, item := range items {
+			decodeValue(slice.Index(i), item, path+"."+strconv.Itoa(i), result)
+		}
+		v.Set(slice)
+	default:
+		if err := parseInto(v, path, value.String(), defaultParseOptions()); err != nil {
+			result.Invalid = append(result.Invalid, asParseError(err))
+		}
+	}
+}
+
+// isScalar tells structs decoded from a single JSON value apart from nested objects.
+func isScalar(t reflect.Type) bool {
+	switch t {
+	case timeType, dateType, dateTimeType:
+		return true
+	}
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func asParseError(err error) *ParseError {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return parseErr
+	}
+	return &ParseError{Err: err}
+}
@@ -0,0 +1,77 @@
+package plugins
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type employee struct {
+	Name string `json:"name" alt:"fullName"`
+	Age  uint8  `json:"age"`
+}
+
+type company struct {
+	INN       string                `json:"inn" alt:"taxId,tin"`
+	Founded   DateFormat            `json:"founded"`
+	Director  string                `json:"management.0.name"`
+	Employees []employee            `json:"employees"`
+	Matrix    [][]int               `json:"matrix"`
+	Currency  string                `json:"currency" default:"USD"`
+	Branches  int                   `json:"branches,omitempty"`
+	Note      *string               `json:"note"`
+	Address   struct{ City string } `json:"address"`
+}
+
+func TestDecode(t *testing.T) {
+	var c company
+	err := Decode(`{
+		"TIN": "7700000000",
+		"Founded": "2001-05-17",
+		"Management": [{"Name": "Ann"}],
+		"employees": [{"FULLNAME": "Bob", "age": 30}, {"name": "Eve", "age": "41"}],
+		"matrix": [[1, 2], [3]],
+		"address": {"city": "Tashkent"}
+	}`, &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := company{
+		INN:       "7700000000",
+		Director:  "Ann",
+		Employees: []employee{{"Bob", 30}, {"Eve", 41}},
+		Matrix:    [][]int{{1, 2}, {3}},
+		Currency:  "USD",
+	}
+	want.Address.City = "Tashkent"
+	founded := time.Time(c.Founded).Format(time.DateOnly)
+	c.Founded = DateFormat{}
+	if founded != "2001-05-17" || !reflect.DeepEqual(c, want) {
+		t.Errorf("got %+v (founded %s)", c, founded)
+	}
+}
+
+func TestDecodeReportsProblems(t *testing.T) {
+	var c company
+	err := Decode(`{"inn": "1", "founded": "yesterday", "employees": [{"name": "Bob", "age": 300}], "matrix": [[1]], "address": "n/a"}`, &c)
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if !reflect.DeepEqual(decodeErr.Missing, []string{"management.0.name"}) {
+		t.Errorf("missing %v", decodeErr.Missing)
+	}
+	var keys []string
+	for _, invalid := range decodeErr.Invalid {
+		keys = append(keys, invalid.Key)
+	}
+	if !reflect.DeepEqual(keys, []string{"founded", "employees.0.age", "address"}) {
+		t.Errorf("invalid %v", keys)
+	}
+	if c.INN != "1" || c.Employees[0].Name != "Bob" {
+		t.Errorf("valid fields are not kept: %+v", c)
+	}
+}
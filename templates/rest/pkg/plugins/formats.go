@@ -1,16 +1,15 @@
 package plugins
 
-import (
-	"fmt"
-	"strconv"
-)
-
+// RoundTo rounds a half-even to precision fractional digits. It rounds the decimal a is
+// printed as, so 2.675 becomes 2.68 even though the float is slightly below it; use
+// Decimal for amounts that must stay exact.
 func RoundTo(a float64, precision int) float64 {
-	format := "%." + strconv.Itoa(precision) + "f"
-
-	str := fmt.Sprintf(format, a)
-	s, _ := strconv.ParseFloat(str, 64)
-	return s
+	d, err := NewDecimalFromFloat(a)
+	if err != nil {
+		// NaN, infinities and magnitudes beyond int64 units have no fraction worth rounding
+		return a
+	}
+	return d.Round(int32(min(max(precision, 0), MaxDecimalScale)), RoundHalfEven).Float64()
 }
 
 func SafeEqualString(s1, s2 *string) bool {
@@ -1,9 +1,16 @@
 package plugins
 
 import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
+
+	customErrors "gotemplate/pkg/errors"
 )
 
 func genericParse[T any](query, splitKey string) T {
@@ -57,6 +64,12 @@ func genericParse[T any](query, splitKey string) T {
 	case *bool:
 		b, _ := strconv.ParseBool(query)
 		return any(&b).(T)
+	case Decimal:
+		d, _ := ParseDecimal(query)
+		return any(d).(T)
+	case *Decimal:
+		d, _ := ParseDecimal(query)
+		return any(&d).(T)
 	case DateFormat:
 		d := parseDateFormat(query)
 		return any(d).(T)
@@ -89,27 +102,43 @@ func genericParse[T any](query, splitKey string) T {
 }
 
 func parseDateFormat(query string) DateFormat {
-	if t, err := time.ParseInLocation(time.RFC3339, query, location); err == nil {
-		return DateFormat(t)
-	}
-	if t, err := time.ParseInLocation(time.DateOnly, query, location); err == nil {
-		return DateFormat(t)
+	d, err := parseDateFormatE(query, location)
+	if err != nil {
+		return DateFormat(GetNow())
 	}
-	return DateFormat(GetNow())
+	return d
+}
+
+func parseDateFormatE(query string, loc *time.Location) (DateFormat, error) {
+	t, err := parseTime(query, loc, dateLayout, time.DateOnly)
+	return DateFormat(t), err
 }
 
 func parseDateTimeFormat(query string) DateTimeFormat {
-	if t, err := time.ParseInLocation(time.RFC3339, query, location); err == nil {
-		return DateTimeFormat(t)
+	d, err := parseDateTimeFormatE(query, location)
+	if err != nil {
+		return DateTimeFormat(GetNow())
 	}
-	if t, err := time.ParseInLocation(time.DateTime, query, location); err == nil {
-		return DateTimeFormat(t)
+	return d
+}
+
+func parseDateTimeFormatE(query string, loc *time.Location) (DateTimeFormat, error) {
+	t, err := parseTime(query, loc, dateTimeLayout, time.DateTime)
+	return DateTimeFormat(t), err
+}
+
+// parseTime accepts the configured layout, RFC 3339 and the given fallback layout.
+func parseTime(query string, loc *time.Location, layout, fallback string) (time.Time, error) {
+	for _, l := range []string{layout, time.RFC3339} {
+		if t, err := time.ParseInLocation(l, query, loc); err == nil {
+			return t, nil
+		}
 	}
-	return DateTimeFormat(GetNow())
+	return time.ParseInLocation(fallback, query, loc)
 }
 
 func genericParseSlice[T any](query, splitKey string) []T {
-	items := strings.Split(query, ",")
+	items := strings.Split(query, splitKey)
 	result := make([]T, 0, len(items))
 	for _, item := range items {
 		item = strings.TrimSpace(item)
@@ -124,3 +153,209 @@ func genericParseSlice[T any](query, splitKey string) []T {
 func ParseQuery[T any](query string) T {
 	return genericParse[T](query, ",")
 }
+
+// ParseError describes a value that does not parse as the requested type.
+type ParseError struct {
+	Key   string
+	Value string
+	Type  string
+	Err   error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %q is not a valid %s", e.Key, e.Value, e.Type)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseQueryE is ParseQuery that reports unparsable values instead of returning zero values.
+// key names the parameter in the error. Slices are split by commas, see ParseQuerySepE.
+func ParseQueryE[T any](key, query string) (T, error) {
+	return ParseQuerySepE[T](key, query, ",")
+}
+
+// ParseQuerySepE is ParseQueryE with slice items separated by sep.
+// An empty query yields the zero value of T. Errors are customErrors.ErrValidation.
+func ParseQuerySepE[T any](key, query, sep string) (T, error) {
+	opts := defaultParseOptions()
+	opts.sep = sep
+	return parseQuery[T](key, query, opts)
+}
+
+// ParseQueryCtxE is ParseQueryE reading dates without an offset in the location of the request.
+func ParseQueryCtxE[T any](ctx context.Context, key, query string) (T, error) {
+	opts := defaultParseOptions()
+	opts.loc = LocationFromContext(ctx)
+	return parseQuery[T](key, query, opts)
+}
+
+func parseQuery[T any](key, query string, opts parseOptions) (T, error) {
+	var result T
+	if query == "" {
+		return result, nil
+	}
+	if err := parseInto(reflect.ValueOf(&result).Elem(), key, query, opts); err != nil {
+		return result, validationError(err)
+	}
+	return result, nil
+}
+
+func validationError(err error) error {
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		return customErrors.WrapValidationError(err)
+	}
+	return customErrors.WithDetails(
+		customErrors.Wrap(err, customErrors.ErrValidation, "invalid_parameter", parseErr.Error()),
+		map[string]any{"key": parseErr.Key, "value": parseErr.Value},
+	)
+}
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	timeType            = reflect.TypeFor[time.Time]()
+	dateType            = reflect.TypeFor[DateFormat]()
+	dateTimeType        = reflect.TypeFor[DateTimeFormat]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// parseOptions control parseInto: sep splits slices, loc is the location of dates without an offset.
+type parseOptions struct {
+	sep string
+	loc *time.Location
+}
+
+func defaultParseOptions() parseOptions {
+	return parseOptions{sep: ",", loc: location}
+}
+
+// parseInto sets v from query. Types implementing encoding.TextUnmarshaler, such as enums,
+// parse themselves.
+func parseInto(v reflect.Value, key, query string, opts parseOptions) error {
+	t := v.Type()
+	fail := func(err error) error {
+		return &ParseError{Key: key, Value: query, Type: typeName(t), Err: err}
+	}
+
+	switch t {
+	case durationType:
+		d, err := time.ParseDuration(query)
+		if err != nil {
+			return fail(err)
+		}
+		v.SetInt(int64(d))
+		return nil
+	case timeType:
+		d, err := parseDateTimeFormatE(query, opts.loc)
+		if err != nil {
+			return fail(err)
+		}
+		v.Set(reflect.ValueOf(time.Time(d)))
+		return nil
+	case dateType:
+		d, err := parseDateFormatE(query, opts.loc)
+		if err != nil {
+			return fail(err)
+		}
+		v.Set(reflect.ValueOf(d))
+		return nil
+	case dateTimeType:
+		d, err := parseDateTimeFormatE(query, opts.loc)
+		if err != nil {
+			return fail(err)
+		}
+		v.Set(reflect.ValueOf(d))
+		return nil
+	}
+
+	if t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(query)); err != nil {
+			return fail(err)
+		}
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(query)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(query)
+		if err != nil {
+			return fail(err)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(query, 10, t.Bits())
+		if err != nil {
+			return fail(err)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(query, 10, t.Bits())
+		if err != nil {
+			return fail(err)
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(query, t.Bits())
+		if err != nil {
+			return fail(err)
+		}
+		v.SetFloat(f)
+	case reflect.Pointer:
+		elem := reflect.New(t.Elem())
+		if err := parseInto(elem.Elem(), key, query, opts); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Slice:
+		items := strings.Split(query, opts.sep)
+		slice := reflect.MakeSlice(t, 0, len(items))
+		for _, item := range items {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			elem := reflect.New(t.Elem()).Elem()
+			if err := parseInto(elem, key, item, opts); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		v.Set(slice)
+	default:
+		return fail(fmt.Errorf("unsupported type %s", t))
+	}
+	return nil
+}
+
+func typeName(t reflect.Type) string {
+	switch t {
+	case durationType:
+		return "duration"
+	case timeType, dateTimeType:
+		return "date-time"
+	case dateType:
+		return "date"
+	}
+	if t.Name() != "" && reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return t.Name()
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeName(t.Elem())
+	case reflect.Slice:
+		return "list of " + typeName(t.Elem())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	default:
+		return "value"
+	}
+}
@@ -0,0 +1,90 @@
+package plugins
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	customErrors "gotemplate/pkg/errors"
+)
+
+type color string
+
+func (c *color) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "red", "green":
+		*c = color(text)
+		return nil
+	}
+	return fmt.Errorf("unknown color %q", text)
+}
+
+func TestParseQueryE(t *testing.T) {
+	check := func(got, want any, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %#v, want %#v", got, want)
+		}
+	}
+
+	u8, err := ParseQueryE[uint8]("n", "200")
+	check(u8, uint8(200), err)
+	i32, err := ParseQueryE[*int32]("n", "-7")
+	check(*i32, int32(-7), err)
+	d, err := ParseQueryE[time.Duration]("ttl", "1m30s")
+	check(d, 90*time.Second, err)
+	c, err := ParseQueryE[[]color]("colors", "red, green")
+	check(c, []color{"red", "green"}, err)
+	ids, err := ParseQuerySepE[[]uint64]("ids", "1|2|3", "|")
+	check(ids, []uint64{1, 2, 3}, err)
+	date, err := ParseQueryE[DateFormat]("from", "2024-02-29")
+	check(time.Time(date).Format(time.DateOnly), "2024-02-29", err)
+	empty, err := ParseQueryE[*int]("n", "")
+	check(empty, (*int)(nil), err)
+}
+
+func TestParseQueryEErrors(t *testing.T) {
+	for name, parse := range map[string]func() error{
+		"int":      func() error { _, err := ParseQueryE[int]("page", "abc"); return err },
+		"overflow": func() error { _, err := ParseQueryE[int8]("page", "300"); return err },
+		"uint":     func() error { _, err := ParseQueryE[uint]("page", "-1"); return err },
+		"date":     func() error { _, err := ParseQueryE[DateFormat]("from", "2024-13-01"); return err },
+		"enum":     func() error { _, err := ParseQueryE[[]color]("colors", "red,blue"); return err },
+		"json":     func() error { _, err := SafeGetE[[]int](gjson.Parse(`{"IDs":[1,"x"]}`).Map(), "ids"); return err },
+	} {
+		err := parse()
+		var parseErr *ParseError
+		if !errors.Is(err, customErrors.ErrValidation) || !errors.As(err, &parseErr) {
+			t.Errorf("%s: expected a validation error, got %v", name, err)
+		}
+	}
+}
+
+func TestSafeGetE(t *testing.T) {
+	m := gjson.Parse(`{"Company":{"INN":"7700000000","Employees":[{"age":30},{"age":41}]},"tags":["a","b"],"since":"2024-01-01"}`).Map()
+
+	inn, err := SafeGetE[string](m, "company.inn")
+	if err != nil || inn != "7700000000" {
+		t.Errorf("inn %q, %v", inn, err)
+	}
+	tags, err := SafeGetE[[]string](m, "tags")
+	if err != nil || !reflect.DeepEqual(tags, []string{"a", "b"}) {
+		t.Errorf("tags %v, %v", tags, err)
+	}
+	missing, err := SafeGetE[*int](m, "missing", "absent")
+	if err != nil || missing != nil {
+		t.Errorf("missing %v, %v", missing, err)
+	}
+	_, err = SafeGetE[int](m, "since")
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Key != "since" || parseErr.Value != "2024-01-01" {
+		t.Errorf("expected the key and value in the error, got %v", err)
+	}
+}
@@ -1,35 +1,58 @@
 package plugins
 
 import (
+	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/tidwall/gjson"
 )
 
+// GetJSONKeyInsensitive resolves a dotted path such as "company.employees.0.name", matching
+// object keys case-insensitively and numeric parts as array indexes. Null values count as missing.
 func GetJSONKeyInsensitive(m map[string]gjson.Result, key string) (*gjson.Result, bool) {
-	keyParts := strings.Split(key, ".")
-	partsLength := len(keyParts)
+	parts := strings.Split(key, ".")
+	value, found := lookupKeyInsensitive(m, parts[0])
+	for _, part := range parts[1:] {
+		if !found {
+			return nil, false
+		}
+		value, found = childInsensitive(value, part)
+	}
+	if !found || !value.Exists() || value.Type == gjson.Null {
+		return nil, false
+	}
+	return &value, true
+}
 
-	for i, part := range keyParts {
-		isReturnPart := partsLength == i+1
+func childInsensitive(value gjson.Result, part string) (gjson.Result, bool) {
+	switch {
+	case value.IsArray():
+		items := value.Array()
+		i, err := strconv.Atoi(part)
+		if err != nil || i < 0 || i >= len(items) {
+			return gjson.Result{}, false
+		}
+		return items[i], true
+	case value.IsObject():
+		return lookupKeyInsensitive(value.Map(), part)
+	default:
+		return gjson.Result{}, false
+	}
+}
 
-		if isReturnPart {
-			for jsonKey, value := range m {
-				if strings.EqualFold(jsonKey, part) && value.Exists() && value.Type != gjson.Null {
-					return &value, true
-				}
-			}
-		} else {
-			for jsonKey, value := range m {
-				if strings.EqualFold(jsonKey, part) {
-					m = value.Map()
-					break
-				}
-			}
+// lookupKeyInsensitive prefers an exact match, so {"inn": null, "INN": "1"} is still found.
+func lookupKeyInsensitive(m map[string]gjson.Result, key string) (gjson.Result, bool) {
+	if value, ok := m[key]; ok && value.Type != gjson.Null {
+		return value, true
+	}
+	for jsonKey, value := range m {
+		if strings.EqualFold(jsonKey, key) && value.Type != gjson.Null {
+			return value, true
 		}
 	}
-	return nil, false
+	return gjson.Result{}, false
 }
 
 func SafeGet[T any](m map[string]gjson.Result, keys ...string) T {
@@ -75,3 +98,53 @@ func SafeGet[T any](m map[string]gjson.Result, keys ...string) T {
 	strVal := val.String()
 	return genericParse[T](strVal, ",")
 }
+
+// SafeGetE is SafeGet that reports a value of the wrong type instead of returning a zero value.
+// A missing key is not an error and yields the zero value of T. JSON arrays fill slices
+// element by element.
+func SafeGetE[T any](m map[string]gjson.Result, keys ...string) (T, error) {
+	var result T
+	if m == nil {
+		return result, nil
+	}
+	for _, key := range keys {
+		val, found := GetJSONKeyInsensitive(m, key)
+		if !found {
+			continue
+		}
+		if err := setFromJSON(reflect.ValueOf(&result).Elem(), key, *val); err != nil {
+			return result, validationError(err)
+		}
+		return result, nil
+	}
+	return result, nil
+}
+
+var gjsonMapType = reflect.TypeFor[map[string]gjson.Result]()
+
+func setFromJSON(v reflect.Value, key string, val gjson.Result) error {
+	t := v.Type()
+	switch {
+	case t == gjsonMapType:
+		v.Set(reflect.ValueOf(val.Map()))
+		return nil
+	case t.Kind() == reflect.Slice && val.IsArray():
+		items := val.Array()
+		slice := reflect.MakeSlice(t, len(items), len(items))
+		for i, item := range items {
+			if err := setFromJSON(slice.Index(i), key+"."+strconv.Itoa(i), item); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	case t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Slice && val.IsArray():
+		elem := reflect.New(t.Elem())
+		if err := setFromJSON(elem.Elem(), key, val); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+	return parseInto(v, key, val.String(), defaultParseOptions())
+}
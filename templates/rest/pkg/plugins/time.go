@@ -1,15 +1,54 @@
 package plugins
 
 import (
+	"context"
+	"sync"
 	"time"
+
+	"4d63.com/tz"
 )
 
 var location = time.UTC
 
+// SetLocation sets the service-wide default, requests may override it with WithLocation.
 func SetLocation(loc *time.Location) {
 	location = loc
 }
 
 func GetNow() time.Time {
-	return time.Now().In(location)
+	return clock.Load().Now().In(location)
+}
+
+type locationKey struct{}
+
+func WithLocation(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, locationKey{}, loc)
+}
+
+// LocationFromContext returns the location of the request, the SetLocation default without one.
+func LocationFromContext(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(locationKey{}).(*time.Location); ok {
+		return loc
+	}
+	return location
+}
+
+// NowCtx is GetNow in the location of the request.
+func NowCtx(ctx context.Context) time.Time {
+	return clock.Load().Now().In(LocationFromContext(ctx))
+}
+
+var locations sync.Map
+
+// LoadLocation loads an IANA time zone from the embedded database and caches it.
+func LoadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := tz.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, loc)
+	return loc, nil
 }
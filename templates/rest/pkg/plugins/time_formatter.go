@@ -1,25 +1,110 @@
 package plugins
 
 import (
+	"context"
+	"database/sql/driver"
+	"fmt"
 	"time"
 )
 
+var (
+	dateLayout     = time.DateOnly
+	dateTimeLayout = time.RFC3339
+)
+
+// SetLayouts changes how DateFormat and DateTimeFormat are written. Parsing accepts the
+// layouts as well as RFC 3339, "2006-01-02" and "2006-01-02 15:04:05".
+func SetLayouts(date, dateTime string) {
+	if date != "" {
+		dateLayout = date
+	}
+	if dateTime != "" {
+		dateTimeLayout = dateTime
+	}
+}
+
+// DateFormat is a calendar date. Like SafeGet, the codecs treat the zero value as
+// absent: it is written as JSON null, an empty string or SQL NULL, and read back from them.
+// The codecs use the SetLocation default, not the location of the request.
 type DateFormat time.Time
 
+// DateTimeFormat is a timestamp with the same zero value handling as DateFormat.
 type DateTimeFormat time.Time
 
-func (m DateFormat) MarshalJSON() ([]byte, error) {
+func (m DateFormat) String() string {
 	t := time.Time(m)
-	return []byte("\"" + t.Format(time.RFC3339) + "\""), nil
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
+
+func (m DateFormat) MarshalJSON() ([]byte, error) {
+	return marshalJSON(m.String())
+}
+
+func (m *DateFormat) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, m.UnmarshalText)
+}
+
+func (m DateFormat) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *DateFormat) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*m = DateFormat{}
+		return nil
+	}
+	d, err := parseDateFormatE(string(text), location)
+	if err != nil {
+		return fmt.Errorf("invalid date %q", text)
+	}
+	*m = d
+	return nil
+}
+
+// UnmarshalParam binds the date from gin query, form and uri parameters.
+func (m *DateFormat) UnmarshalParam(param string) error {
+	return m.UnmarshalText([]byte(param))
+}
+
+func (m *DateFormat) Scan(src any) error {
+	t, err := scanTime(src, m.UnmarshalText)
+	if err == nil && t != nil {
+		*m = DateFormat(*t)
+	}
+	return err
+}
+
+func (m DateFormat) Value() (driver.Value, error) {
+	if time.Time(m).IsZero() {
+		return nil, nil
+	}
+	return time.Time(m), nil
 }
 
 func (m DateFormat) GetTime() time.Time {
+	return m.getTimeIn(location)
+}
+
+// GetTimeCtx is GetTime in the location of the request, see WithLocation.
+func (m DateFormat) GetTimeCtx(ctx context.Context) time.Time {
+	return m.getTimeIn(LocationFromContext(ctx))
+}
+
+// InContext moves the date to the location of the request, so it renders in the caller's time zone.
+func (m DateFormat) InContext(ctx context.Context) DateFormat {
+	return DateFormat(m.GetTimeCtx(ctx))
+}
+
+func (m DateFormat) getTimeIn(loc *time.Location) time.Time {
 	t := time.Time(m)
 	if t.IsZero() {
 		return t
 	}
 
-	t = t.In(location)
+	t = t.In(loc)
 	return time.Date(
 		t.Year(), t.Month(), t.Day(),
 		0, 0, 0, 0,
@@ -27,17 +112,79 @@ func (m DateFormat) GetTime() time.Time {
 	)
 }
 
-func (m DateTimeFormat) MarshalJSON() ([]byte, error) {
+func (m DateTimeFormat) String() string {
 	t := time.Time(m)
-	return []byte("\"" + t.Format(time.RFC3339) + "\""), nil
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateTimeLayout)
+}
+
+func (m DateTimeFormat) MarshalJSON() ([]byte, error) {
+	return marshalJSON(m.String())
+}
+
+func (m *DateTimeFormat) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(data, m.UnmarshalText)
+}
+
+func (m DateTimeFormat) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *DateTimeFormat) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*m = DateTimeFormat{}
+		return nil
+	}
+	d, err := parseDateTimeFormatE(string(text), location)
+	if err != nil {
+		return fmt.Errorf("invalid date-time %q", text)
+	}
+	*m = d
+	return nil
+}
+
+// UnmarshalParam binds the timestamp from gin query, form and uri parameters.
+func (m *DateTimeFormat) UnmarshalParam(param string) error {
+	return m.UnmarshalText([]byte(param))
+}
+
+func (m *DateTimeFormat) Scan(src any) error {
+	t, err := scanTime(src, m.UnmarshalText)
+	if err == nil && t != nil {
+		*m = DateTimeFormat(*t)
+	}
+	return err
+}
+
+func (m DateTimeFormat) Value() (driver.Value, error) {
+	if time.Time(m).IsZero() {
+		return nil, nil
+	}
+	return time.Time(m), nil
 }
 
 func (m DateTimeFormat) GetTime() time.Time {
+	return m.getTimeIn(location)
+}
+
+// GetTimeCtx is GetTime in the location of the request, see WithLocation.
+func (m DateTimeFormat) GetTimeCtx(ctx context.Context) time.Time {
+	return m.getTimeIn(LocationFromContext(ctx))
+}
+
+// InContext moves the timestamp to the location of the request, so it renders in the caller's time zone.
+func (m DateTimeFormat) InContext(ctx context.Context) DateTimeFormat {
+	return DateTimeFormat(m.GetTimeCtx(ctx))
+}
+
+func (m DateTimeFormat) getTimeIn(loc *time.Location) time.Time {
 	t := time.Time(m)
 	if t.IsZero() {
 		return t
 	}
-	return t.In(location)
+	return t.In(loc)
 }
 
 func (m DateFormat) GetTimePtr() *time.Time {
@@ -55,3 +202,38 @@ func (m DateTimeFormat) GetTimePtr() *time.Time {
 	}
 	return &t
 }
+
+func marshalJSON(s string) ([]byte, error) {
+	if s == "" {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + s + `"`), nil
+}
+
+func unmarshalJSON(data []byte, unmarshalText func([]byte) error) error {
+	s := string(data)
+	if s == "null" {
+		return unmarshalText(nil)
+	}
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return fmt.Errorf("date must be a JSON string, got %s", s)
+	}
+	return unmarshalText([]byte(s[1 : len(s)-1]))
+}
+
+// scanTime returns the time of a driver value; strings are handed to unmarshalText,
+// which stores the result itself, and nil t is returned for them.
+func scanTime(src any, unmarshalText func([]byte) error) (*time.Time, error) {
+	switch v := src.(type) {
+	case nil:
+		return &time.Time{}, nil
+	case time.Time:
+		return &v, nil
+	case string:
+		return nil, unmarshalText([]byte(v))
+	case []byte:
+		return nil, unmarshalText(v)
+	default:
+		return nil, fmt.Errorf("cannot scan %T into a date", src)
+	}
+}
@@ -0,0 +1,83 @@
+package plugins
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type event struct {
+	Day   DateFormat      `json:"day" form:"day"`
+	At    DateTimeFormat  `json:"at" form:"at"`
+	Until *DateTimeFormat `json:"until" form:"until"`
+}
+
+func TestDateFormatJSON(t *testing.T) {
+	var e event
+	if err := json.Unmarshal([]byte(`{"day":"2024-02-29","at":"2024-02-29T10:00:00+05:00","until":null}`), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Until != nil {
+		t.Errorf("null produced %v", e.Until)
+	}
+
+	out, _ := json.Marshal(e)
+	if want := `{"day":"2024-02-29","at":"2024-02-29T10:00:00+05:00","until":null}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+
+	out, _ = json.Marshal(event{})
+	if want := `{"day":null,"at":null,"until":null}`; string(out) != want {
+		t.Errorf("zero values: got %s, want %s", out, want)
+	}
+	if err := json.Unmarshal([]byte(`{"day":"29.02.2024"}`), &e); err == nil {
+		t.Error("expected an error for an unknown layout")
+	}
+}
+
+func TestDateFormatLayouts(t *testing.T) {
+	SetLayouts("02.01.2006", "")
+	t.Cleanup(func() { SetLayouts(time.DateOnly, time.RFC3339) })
+
+	var d DateFormat
+	if err := d.UnmarshalText([]byte("29.02.2024")); err != nil {
+		t.Fatal(err)
+	}
+	if d.String() != "29.02.2024" {
+		t.Errorf("got %s", d)
+	}
+	if err := d.UnmarshalText([]byte("2024-03-01")); err != nil || d.String() != "01.03.2024" {
+		t.Errorf("ISO dates are still accepted: %s, %v", d, err)
+	}
+}
+
+func TestDateFormatSQLAndBinding(t *testing.T) {
+	var d DateFormat
+	if err := d.Scan("2024-02-29"); err != nil || d.String() != "2024-02-29" {
+		t.Errorf("scan string: %s, %v", d, err)
+	}
+	if err := d.Scan(nil); err != nil || !time.Time(d).IsZero() {
+		t.Errorf("scan NULL: %s, %v", d, err)
+	}
+	if v, _ := d.Value(); v != nil {
+		t.Errorf("zero date is stored as %v", v)
+	}
+	at := time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC)
+	if v, _ := DateTimeFormat(at).Value(); v != at {
+		t.Errorf("value %v", v)
+	}
+
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("GET", "/?day=2024-02-29&until=2024-03-01%2012:00:00", nil)
+	var e event
+	if err := ctx.ShouldBindQuery(&e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Day.String() != "2024-02-29" || e.Until == nil || time.Time(*e.Until).Hour() != 12 || !time.Time(e.At).IsZero() {
+		t.Errorf("bound %+v", e)
+	}
+}
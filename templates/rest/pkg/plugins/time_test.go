@@ -0,0 +1,41 @@
+package plugins
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestClockAndContextLocation(t *testing.T) {
+	start := time.Date(2024, 3, 1, 23, 30, 0, 0, time.UTC)
+	fake := NewFakeClock(start)
+	SetClock(fake)
+	t.Cleanup(func() { SetClock(RealClock{}) })
+
+	tokyo, err := LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithLocation(context.Background(), tokyo)
+
+	if now := NowCtx(ctx); !now.Equal(start) || now.Location() != tokyo {
+		t.Errorf("NowCtx = %v", now)
+	}
+	fake.Advance(time.Hour)
+	if now := GetNow(); !now.Equal(start.Add(time.Hour)) {
+		t.Errorf("GetNow = %v after Advance", now)
+	}
+
+	// 23:30 UTC is already the next day in Tokyo
+	if day := DateFormat(start).GetTimeCtx(ctx).Day(); day != 2 {
+		t.Errorf("date in Tokyo is day %d", day)
+	}
+
+	parsed, err := ParseQueryCtxE[DateTimeFormat](ctx, "from", "2024-03-02 08:30:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !time.Time(parsed).Equal(start) {
+		t.Errorf("parsed %v, want %v", time.Time(parsed), start)
+	}
+}